@@ -0,0 +1,332 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// Authenticator provides the credentials for API requests.
+// The built-in implementations are PasswordAuth (default), APIKeyAuth and TokenAuth.
+// Custom implementations can be registered with the Auth modifier.
+// An Authenticator is shared by all copies of a Client and must be safe for concurrent use.
+type Authenticator interface {
+	// Refresh makes sure valid credentials are available, e.g. by logging in if no token is available or the token has expired.
+	Refresh(ctx context.Context, client *Client) error
+	// Apply adds the credentials to a request.
+	Apply(req *http.Request) error
+	// Invalidate discards the credentials applied to req after the request was rejected with HTTP status 401.
+	// It reports whether the request should be retried after calling Refresh.
+	Invalidate(req *http.Request, body []byte) bool
+}
+
+// LogoutAuthenticator is implemented by authenticators whose session can be ended explicitly, see Client.Logout.
+type LogoutAuthenticator interface {
+	Authenticator
+	// Logout ends the session and discards the credentials.
+	Logout(ctx context.Context, client *Client) error
+}
+
+// Auth sets a custom authenticator, which replaces the default password login, e.g.
+//
+//	client, _ := nd.NewClient("https://10.1.1.1", "/appcenter/cisco/ndfc/api/v1", "", "", "", true, nd.Auth(myAuthenticator))
+func Auth(auth Authenticator) func(*Client) {
+	return func(client *Client) {
+		client.Auth = auth
+	}
+}
+
+// APIKey configures API key authentication, where the key and username are sent
+// as X-Nd-Apikey and X-Nd-Username headers with every request instead of logging in with a password.
+func APIKey(usr, key string) func(*Client) {
+	return func(client *Client) {
+		client.Usr = usr
+		client.Auth = NewAPIKeyAuth(usr, key)
+	}
+}
+
+// BearerToken configures a static bearer token, which is sent with every request instead of logging in with a password.
+func BearerToken(token string) func(*Client) {
+	return func(client *Client) {
+		client.Auth = NewTokenAuth(token)
+	}
+}
+
+// PasswordAuth authenticates with the Nexus Dashboard username, password and domain of the client.
+// The token obtained by logging in is refreshed once the token timeout has been reached, i.e. half of the
+// JWT session timeout configured on Nexus Dashboard. A full login is only done if the refresh is rejected
+// or the session has been idle for longer than the configured idle session timeout.
+type PasswordAuth struct {
+	session
+}
+
+// NewPasswordAuth creates a new password authenticator.
+func NewPasswordAuth() *PasswordAuth {
+	return &PasswordAuth{}
+}
+
+// Refresh refreshes the token if the token timeout has been reached and logs in if no token is available,
+// the refresh failed or the session is idle.
+// Concurrent calls wait for a single login to complete.
+func (auth *PasswordAuth) Refresh(ctx context.Context, client *Client) error {
+	client.Logger.Log(ctx, LevelTrace, "Attempting authentication")
+	if auth.valid() {
+		return nil
+	}
+	auth.loginMutex.Lock()
+	defer auth.loginMutex.Unlock()
+	// Another goroutine might have logged in while waiting for the lock
+	token, timeStamp, timeout := auth.get()
+	if token == "" {
+		client.Logger.DebugContext(ctx, "No token available, attempting login")
+	} else if time.Since(timeStamp) <= timeout {
+		return nil
+	} else if auth.idle() {
+		client.Logger.DebugContext(ctx, "Session idle timeout reached, attempting login")
+	} else {
+		client.Logger.DebugContext(ctx, "Token timeout reached, attempting token refresh")
+		err := auth.RefreshToken(ctx, client)
+		if err == nil {
+			return nil
+		}
+		client.Logger.DebugContext(ctx, "Token refresh failed, attempting login", "error", err)
+	}
+	err := auth.Login(ctx, client)
+	if err == nil {
+		auth.checkAndFillTokenTimeout(ctx, client)
+	}
+	client.Logger.Log(ctx, LevelTrace, "Authentication complete")
+	return err
+}
+
+// Login authenticates to the Nexus Dashboard instance and stores the obtained token.
+func (auth *PasswordAuth) Login(ctx context.Context, client *Client) error {
+	body := ""
+	body, _ = sjson.Set(body, "userName", client.Usr)
+	body, _ = sjson.Set(body, "userPasswd", client.Pwd)
+	body, _ = sjson.Set(body, "domain", client.Domain)
+	req := client.NewReq("POST", "/login", strings.NewReader(body), NoLogPayload, Context(ctx))
+	client.Logger.Log(ctx, LevelTrace, "Client login: starting HTTP request")
+	httpRes, err := client.send(req.HttpReq)
+	if err != nil {
+		client.Logger.ErrorContext(ctx, "Client login: HTTP request failed", "error", err)
+		return err
+	}
+	defer httpRes.Body.Close()
+	bodyBytes, _ := io.ReadAll(httpRes.Body)
+	if httpRes.StatusCode != 200 {
+		client.Logger.ErrorContext(ctx, "Authentication failed", "status", httpRes.StatusCode)
+		return fmt.Errorf("Authentication failed: %w", newAPIError(req.HttpReq, httpRes.StatusCode, bodyBytes, false))
+	}
+	res := Res(gjson.ParseBytes(bodyBytes))
+	token := res.Get("token").String()
+	if token == "" {
+		client.Logger.ErrorContext(ctx, "Token retrieval failed: no token in payload")
+		return fmt.Errorf("Authentication failed")
+	}
+	auth.set(token, time.Now())
+	client.Logger.DebugContext(ctx, "Authentication successful")
+	return nil
+}
+
+// RefreshToken obtains a new token from Nexus Dashboard using the current token, without sending the password again.
+func (auth *PasswordAuth) RefreshToken(ctx context.Context, client *Client) error {
+	token := auth.Token()
+	if token == "" {
+		return errors.New("Token refresh failed: no token available")
+	}
+	req := client.NewReq("POST", "/refresh", strings.NewReader("{}"), NoLogPayload, Context(ctx))
+	req.HttpReq.Header.Set("Authorization", "Bearer "+token)
+	httpRes, err := client.send(req.HttpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+	bodyBytes, _ := io.ReadAll(httpRes.Body)
+	if httpRes.StatusCode != 200 {
+		return fmt.Errorf("Token refresh failed: %w", newAPIError(req.HttpReq, httpRes.StatusCode, bodyBytes, false))
+	}
+	res := Res(gjson.ParseBytes(bodyBytes))
+	newToken := res.Get("token").String()
+	if newToken == "" {
+		newToken = res.Get("jwttoken").String()
+	}
+	if newToken == "" {
+		return errors.New("Token refresh failed: no token in payload")
+	}
+	auth.set(newToken, time.Now())
+	client.Logger.DebugContext(ctx, "Token refresh successful")
+	return nil
+}
+
+// Logout ends the session on Nexus Dashboard and discards the token.
+// The token is discarded even if the logout request fails.
+func (auth *PasswordAuth) Logout(ctx context.Context, client *Client) error {
+	auth.loginMutex.Lock()
+	defer auth.loginMutex.Unlock()
+	token := auth.Token()
+	if token == "" {
+		return nil
+	}
+	auth.set("", time.Time{})
+	req := client.NewReq("POST", "/logout", strings.NewReader("{}"), NoLogPayload, Context(ctx))
+	req.HttpReq.Header.Set("Authorization", "Bearer "+token)
+	httpRes, err := client.send(req.HttpReq)
+	if err != nil {
+		client.Logger.ErrorContext(ctx, "Client logout: HTTP request failed", "error", err)
+		return err
+	}
+	defer httpRes.Body.Close()
+	bodyBytes, _ := io.ReadAll(httpRes.Body)
+	if httpRes.StatusCode < 200 || httpRes.StatusCode > 299 {
+		client.Logger.ErrorContext(ctx, "Logout failed", "status", httpRes.StatusCode)
+		return fmt.Errorf("Logout failed: %w", newAPIError(req.HttpReq, httpRes.StatusCode, bodyBytes, false))
+	}
+	client.Logger.DebugContext(ctx, "Logout successful")
+	return nil
+}
+
+func (auth *PasswordAuth) checkAndFillTokenTimeout(ctx context.Context, client *Client) {
+	// Sent directly instead of with Do, which would attempt a new login on a rejected token
+	// while loginMutex is held by Refresh.
+	req := client.NewReq("GET", "/api/config/dn/apigwcfg/default", nil, NoLogPayload, Context(ctx))
+	req.HttpReq.Header.Set("Authorization", "Bearer "+auth.Token())
+	httpRes, err := client.send(req.HttpReq)
+	if err != nil {
+		client.Logger.ErrorContext(ctx, "Get API config failed", "error", err)
+		return
+	}
+	defer httpRes.Body.Close()
+	bodyBytes, _ := io.ReadAll(httpRes.Body)
+	if httpRes.StatusCode != 200 {
+		client.Logger.ErrorContext(ctx, "Get API config failed", "error", newAPIError(req.HttpReq, httpRes.StatusCode, bodyBytes, false))
+		return
+	}
+	result := Res(gjson.ParseBytes(bodyBytes))
+	/* Response Format
+			{
+			"config": {
+				"idle_session_timeout_sec": 3600,
+				"jwt_session_timeout_sec": 1200,
+				"log_level": "info"
+			},
+	    }
+	*/
+	if idleTimeout := result.Get("config.idle_session_timeout_sec").Int(); idleTimeout > 0 {
+		auth.setIdleTimeout(time.Duration(idleTimeout) * time.Second)
+	}
+	tokenTimeout := result.Get("config.jwt_session_timeout_sec").Int()
+	if tokenTimeout > 0 {
+		auth.setTimeout(time.Duration(tokenTimeout/2) * time.Second)
+		client.Logger.InfoContext(ctx, "Token timeout set", "timeout", auth.AuthTokenTimeout())
+		return
+	}
+	auth.setTimeout(2 * time.Minute)
+	client.Logger.ErrorContext(ctx, "Token timeout could not be read, using default value", "timeout", auth.AuthTokenTimeout())
+}
+
+// Apply adds the token as bearer token to the Authorization header.
+func (auth *PasswordAuth) Apply(req *http.Request) error {
+	auth.touch()
+	req.Header.Set("Authorization", "Bearer "+auth.Token())
+	return nil
+}
+
+// Invalidate discards the token applied to req if the request was rejected because of an expired token.
+// Only the first request rejected with a given token triggers a new login.
+func (auth *PasswordAuth) Invalidate(req *http.Request, body []byte) bool {
+	if !strings.Contains(string(body), "token has expired") {
+		return false
+	}
+	auth.invalidate(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	return true
+}
+
+// Token returns the current authentication token.
+func (auth *PasswordAuth) Token() string {
+	token, _, _ := auth.get()
+	return token
+}
+
+// AuthTimeStamp returns the time the current authentication token was obtained.
+func (auth *PasswordAuth) AuthTimeStamp() time.Time {
+	_, timeStamp, _ := auth.get()
+	return timeStamp
+}
+
+// AuthTokenTimeout returns the duration after which the authentication token is renewed.
+func (auth *PasswordAuth) AuthTokenTimeout() time.Duration {
+	_, _, timeout := auth.get()
+	return timeout
+}
+
+// APIKeyAuth authenticates with a long-lived Nexus Dashboard API key.
+type APIKeyAuth struct {
+	// Usr is the Nexus Dashboard username the API key belongs to.
+	Usr string
+	// Key is the Nexus Dashboard API key.
+	Key string
+}
+
+// NewAPIKeyAuth creates a new API key authenticator.
+func NewAPIKeyAuth(usr, key string) *APIKeyAuth {
+	return &APIKeyAuth{Usr: usr, Key: key}
+}
+
+// Refresh does nothing as API keys do not expire.
+func (auth *APIKeyAuth) Refresh(ctx context.Context, client *Client) error {
+	return nil
+}
+
+// Apply adds the X-Nd-Username and X-Nd-Apikey headers.
+func (auth *APIKeyAuth) Apply(req *http.Request) error {
+	req.Header.Set("X-Nd-Username", auth.Usr)
+	req.Header.Set("X-Nd-Apikey", auth.Key)
+	return nil
+}
+
+// Invalidate reports false as a rejected API key cannot be renewed.
+func (auth *APIKeyAuth) Invalidate(req *http.Request, body []byte) bool {
+	return false
+}
+
+// TokenAuth authenticates with a static bearer token obtained outside of the client.
+type TokenAuth struct {
+	token string
+}
+
+// NewTokenAuth creates a new static bearer token authenticator.
+func NewTokenAuth(token string) *TokenAuth {
+	return &TokenAuth{token: token}
+}
+
+// Refresh returns an error if no token is configured.
+func (auth *TokenAuth) Refresh(ctx context.Context, client *Client) error {
+	if auth.token == "" {
+		return errors.New("Authentication failed: no token configured")
+	}
+	return nil
+}
+
+// Apply adds the token as bearer token to the Authorization header.
+func (auth *TokenAuth) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+auth.token)
+	return nil
+}
+
+// Invalidate reports false as a static token cannot be renewed.
+func (auth *TokenAuth) Invalidate(req *http.Request, body []byte) bool {
+	return false
+}
+
+// Token returns the configured token.
+func (auth *TokenAuth) Token() string {
+	return auth.token
+}
@@ -0,0 +1,200 @@
+package nd
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// agentAuth is a custom authenticator fetching short-lived credentials.
+type agentAuth struct {
+	refreshes atomic.Int32
+	token     atomic.Value
+}
+
+func (auth *agentAuth) Refresh(ctx context.Context, client *Client) error {
+	if v, _ := auth.token.Load().(string); v == "" {
+		auth.refreshes.Add(1)
+		auth.token.Store("AGENT")
+	}
+	return nil
+}
+
+func (auth *agentAuth) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+auth.token.Load().(string))
+	return nil
+}
+
+func (auth *agentAuth) Invalidate(req *http.Request, body []byte) bool {
+	auth.token.Store("")
+	return true
+}
+
+// TestAPIKeyAuth tests the APIKey modifier.
+func TestAPIKeyAuth(t *testing.T) {
+	defer gock.Off()
+	client, _ := NewClient(testURL, "/", "", "", "", true, MaxRetries(0), APIKey("admin", "KEY"))
+	gock.InterceptClient(client.HttpClient)
+
+	// No login request is made
+	gock.New(testURL).
+		Get("/url").
+		MatchHeader("X-Nd-Username", "admin").
+		MatchHeader("X-Nd-Apikey", "KEY").
+		Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, "", client.Token())
+}
+
+// TestTokenAuth tests the BearerToken modifier.
+func TestTokenAuth(t *testing.T) {
+	defer gock.Off()
+	client, _ := NewClient(testURL, "/", "", "", "", true, MaxRetries(0), BearerToken("STATIC"))
+	gock.InterceptClient(client.HttpClient)
+
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer STATIC").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "STATIC", client.Token())
+
+	// Rejected token is not renewed
+	gock.New(testURL).Get("/url").Reply(401).BodyString(`{"message": "token has expired"}`)
+	_, err = client.Get("/url")
+	assert.True(t, IsUnauthorized(err))
+
+	// Missing token
+	BearerToken("")(&client)
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+}
+
+// TestCustomAuth tests the Auth modifier with a custom authenticator.
+func TestCustomAuth(t *testing.T) {
+	defer gock.Off()
+	auth := &agentAuth{}
+	client, _ := NewClient(testURL, "/", "", "", "", true, MaxRetries(1), BackoffMinDelay(0), BackoffMaxDelay(0), Auth(auth))
+	gock.InterceptClient(client.HttpClient)
+
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer AGENT").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), auth.refreshes.Load())
+
+	// Rejected credentials are refreshed
+	gock.New(testURL).Get("/url").Reply(401)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer AGENT").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), auth.refreshes.Load())
+	assert.True(t, gock.IsDone())
+}
+
+// TestPasswordAuthRefresh tests the token refresh of the PasswordAuth authenticator.
+func TestPasswordAuthRefresh(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	auth := client.Auth.(*PasswordAuth)
+
+	// Token timeout reached, token is refreshed
+	auth.set("ABC", time.Now().Add(-time.Hour))
+	gock.New(testURL).Post("/refresh").MatchHeader("Authorization", "Bearer ABC").Reply(200).BodyString(`{"token": "REFRESHED"}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer REFRESHED").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// Refresh rejected, falls back to login
+	auth.set("REFRESHED", time.Now().Add(-time.Hour))
+	gock.New(testURL).Post("/refresh").Reply(401)
+	gock.New(testURL).Post("/login").Reply(200).BodyString(`{"token": "LOGIN"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{"config": {"idle_session_timeout_sec": 60, "jwt_session_timeout_sec": 1200}}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer LOGIN").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// Idle timeout reached, no refresh is attempted
+	auth.set("LOGIN", time.Now().Add(-time.Hour))
+	gock.New(testURL).Post("/login").Reply(200).BodyString(`{"token": "IDLE"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer IDLE").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestPasswordAuthExpiredTokenTimeout tests that a rejected token while reading the token timeout does not trigger a new login.
+func TestPasswordAuthExpiredTokenTimeout(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.MaxRetries = 1
+	auth := client.Auth.(*PasswordAuth)
+	auth.set("", time.Time{})
+
+	gock.New(testURL).Post("/login").Times(1).Reply(200).BodyString(`{"token": "ABC"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(401).BodyString(`{"message": "token has expired"}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer ABC").Reply(200)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Get("/url")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("authentication deadlocked")
+	}
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientLogout tests the Client::Logout method.
+func TestClientLogout(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Successful logout
+	gock.New(testURL).Post("/logout").MatchHeader("Authorization", "Bearer ABC").Reply(204)
+	assert.NoError(t, client.Logout())
+	assert.Equal(t, "", client.Token())
+	assert.True(t, client.AuthTimeStamp().IsZero())
+
+	// Not logged in
+	assert.NoError(t, client.Logout())
+
+	// Failed logout discards token
+	client.Auth.(*PasswordAuth).set("ABC", time.Now())
+	gock.New(testURL).Post("/logout").Reply(500)
+	assert.Error(t, client.Logout())
+	assert.Equal(t, "", client.Token())
+
+	// API key authentication
+	APIKey("admin", "KEY")(&client)
+	assert.NoError(t, client.Logout())
+}
+
+// TestClientWithSession tests the Client::WithSession method.
+func TestClientWithSession(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+
+	gock.New(testURL).Post("/login").Reply(200).BodyString(`{"token": "ABC"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{}`)
+	gock.New(testURL).Get("/url").Reply(200)
+	gock.New(testURL).Post("/logout").MatchHeader("Authorization", "Bearer ABC").Reply(200)
+	err := client.WithSession(func(client *Client) error {
+		_, err := client.Get("/url")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, "", client.Token())
+}
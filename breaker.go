@@ -0,0 +1,160 @@
+package nd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by CircuitOpenError using errors.Is.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitOpenError is returned without sending the request while the circuit breaker is open.
+type CircuitOpenError struct {
+	// Until is the time when the circuit breaker allows a probe request.
+	Until time.Time
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open until %s", e.Until.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets all requests pass.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all requests with a CircuitOpenError.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe request pass to test whether Nexus Dashboard has recovered.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// CircuitBreaker enables a circuit breaker, which opens after threshold consecutive connection errors
+// or HTTP 5xx responses. While open, requests fail immediately with a CircuitOpenError.
+// After cooldown a single probe request is let through, which closes the circuit breaker if it succeeds.
+// The optional onChange functions are called on every state change, e.g. to report the state in health checks.
+// The circuit breaker is shared by all copies of the client.
+func CircuitBreaker(threshold int, cooldown time.Duration, onChange ...func(from, to BreakerState)) func(*Client) {
+	return func(client *Client) {
+		client.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+			onChange:  onChange,
+		}
+	}
+}
+
+// CircuitBreakerState returns the state of the circuit breaker, BreakerClosed if no circuit breaker is configured.
+func (client *Client) CircuitBreakerState() BreakerState {
+	if client.breaker == nil {
+		return BreakerClosed
+	}
+	client.breaker.mutex.Lock()
+	defer client.breaker.mutex.Unlock()
+	return client.breaker.state
+}
+
+type circuitBreaker struct {
+	mutex     sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	onChange  []func(from, to BreakerState)
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// allow returns an error if the request must not be sent.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mutex.Lock()
+	from := b.state
+	switch b.state {
+	case BreakerOpen:
+		until := b.openedAt.Add(b.cooldown)
+		if time.Now().Before(until) {
+			b.mutex.Unlock()
+			return &CircuitOpenError{Until: until}
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			until := time.Now().Add(b.cooldown)
+			b.mutex.Unlock()
+			return &CircuitOpenError{Until: until}
+		}
+		b.probing = true
+	}
+	to := b.state
+	b.mutex.Unlock()
+	b.notify(from, to)
+	return nil
+}
+
+// record records the outcome of a request.
+func (b *circuitBreaker) record(success bool) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	from := b.state
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.state = BreakerClosed
+	} else {
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+	to := b.state
+	b.mutex.Unlock()
+	b.notify(from, to)
+}
+
+// abort releases the probe slot of a request which was not sent.
+func (b *circuitBreaker) abort() {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) notify(from, to BreakerState) {
+	if from == to {
+		return
+	}
+	for _, fn := range b.onChange {
+		fn(from, to)
+	}
+}
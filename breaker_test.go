@@ -0,0 +1,119 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestCircuitBreaker tests the CircuitBreaker modifier.
+func TestCircuitBreaker(t *testing.T) {
+	defer gock.Off()
+	var changes []string
+	client := authenticatedTestClient()
+	CircuitBreaker(2, 50*time.Millisecond, func(from, to BreakerState) {
+		changes = append(changes, from.String()+"->"+to.String())
+	})(&client)
+
+	// Opens after consecutive failures
+	gock.New(testURL).Get("/url").ReplyError(errors.New("fail"))
+	gock.New(testURL).Get("/url").Reply(502)
+	_, err := client.Get("/url")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerClosed, client.CircuitBreakerState())
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerOpen, client.CircuitBreakerState())
+
+	// Fails fast while open
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	var openErr *CircuitOpenError
+	assert.True(t, errors.As(err, &openErr))
+	assert.True(t, gock.IsDone())
+
+	// Failed probe opens again
+	time.Sleep(60 * time.Millisecond)
+	gock.New(testURL).Get("/url").Reply(503)
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerOpen, client.CircuitBreakerState())
+
+	// Successful probe closes
+	time.Sleep(60 * time.Millisecond)
+	gock.New(testURL).Get("/url").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, client.CircuitBreakerState())
+
+	// Client errors do not count as failures
+	gock.New(testURL).Get("/url").Times(3).Reply(404)
+	for i := 0; i < 3; i++ {
+		_, err = client.Get("/url")
+		assert.True(t, IsNotFound(err))
+	}
+	assert.Equal(t, BreakerClosed, client.CircuitBreakerState())
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, changes)
+}
+
+// TestCircuitBreakerCanceled tests that requests canceled by the caller do not open the circuit breaker.
+func TestCircuitBreakerCanceled(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	CircuitBreaker(2, time.Minute)(&client)
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		gock.New(testURL).Get("/url").
+			AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+				cancel()
+				return true, nil
+			}).
+			ReplyError(context.Canceled)
+		_, err := client.Get("/url", Context(ctx))
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, BreakerClosed, client.CircuitBreakerState())
+
+	gock.New(testURL).Get("/url").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+}
+
+// TestCircuitBreakerLimits tests that requests waiting for limits do not hold the half-open probe.
+func TestCircuitBreakerLimits(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	CircuitBreaker(1, 20*time.Millisecond)(&client)
+	PathRateLimit("GET", "/limited", 0.001, 1)(&client)
+
+	gock.New(testURL).Get("/limited").Reply(200)
+	_, err := client.Get("/limited")
+	assert.NoError(t, err)
+	gock.New(testURL).Get("/other").Reply(503)
+	_, err = client.Get("/other")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerOpen, client.CircuitBreakerState())
+	time.Sleep(30 * time.Millisecond)
+
+	// The rate limited request waits without taking the probe
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := client.Get("/limited", Context(ctx))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	gock.New(testURL).Get("/other").Reply(200)
+	_, err = client.Get("/other")
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, client.CircuitBreakerState())
+	assert.Error(t, <-done)
+}
@@ -3,22 +3,18 @@ package nd
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
-	"log"
-	"math"
-	"math/rand"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/tidwall/gjson"
-	"github.com/tidwall/sjson"
 )
 
 const DefaultMaxRetries int = 3
@@ -29,15 +25,18 @@ const DefaultBackoffDelayFactor float64 = 3
 // Client is an HTTP Nexus Dashboard client.
 // Use nd.NewClient to initiate a client.
 // This will ensure proper cookie handling and processing of modifiers.
+//
+// A Client is safe for concurrent use by multiple goroutines.
+// Copies of a Client share the same Authenticator,
+// i.e. a token obtained by one copy is used by all others.
 type Client struct {
 	// HttpClient is the *http.Client used for API requests.
 	HttpClient *http.Client
 	// Url is the Nexus Dashboard IP or hostname, e.g. https://10.0.0.1:443 (port is optional).
+	// It is the primary node if further cluster nodes are added with Nodes.
 	Url string
 	// BasePath is the Nexus Dashboard URL prefix to use, e.g. '/appcenter/cisco/ndfc/api/v1'.
 	BasePath string
-	// Token is the current authentication token
-	Token string
 	// Usr is the Nexus Dashboard username.
 	Usr string
 	// Pwd is the Nexus Dashboard password.
@@ -54,12 +53,20 @@ type Client struct {
 	BackoffMaxDelay int
 	// Backoff delay factor
 	BackoffDelayFactor float64
-	// Authentication mutex
-	AuthenticationMutex *sync.Mutex
-	// Authentication timestamp
-	AuthTimeStamp time.Time
-	// Authentication token timeout
-	AuthTokenTimeout time.Duration
+	// RetryPolicy decides whether and when failed requests are retried, nil uses the default policy based on the backoff settings
+	RetryPolicy RetryPolicy
+	// Logger is the structured logger used for debug and error messages
+	Logger *slog.Logger
+	// Redactor removes sensitive values from logged payloads and headers, nil disables redaction
+	Redactor *Redactor
+	// Auth provides the credentials for API requests, by default the password login with Usr, Pwd and Domain
+	Auth Authenticator
+	// limits are the rate and concurrency limits shared by all copies of the client
+	limits *limits
+	// breaker is the optional circuit breaker shared by all copies of the client
+	breaker *circuitBreaker
+	// nodes are the cluster nodes shared by all copies of the client
+	nodes *nodePool
 }
 
 // NewClient creates a new Nexus Dashboard HTTP client.
@@ -89,25 +96,33 @@ func NewClient(url, basePath, usr, pwd, domain string, insecure bool, mods ...fu
 	}
 
 	client := Client{
-		HttpClient:          &httpClient,
-		Url:                 url,
-		BasePath:            basePath,
-		Usr:                 usr,
-		Pwd:                 pwd,
-		Domain:              domain,
-		Insecure:            insecure,
-		MaxRetries:          DefaultMaxRetries,
-		BackoffMinDelay:     DefaultBackoffMinDelay,
-		BackoffMaxDelay:     DefaultBackoffMaxDelay,
-		BackoffDelayFactor:  DefaultBackoffDelayFactor,
-		AuthenticationMutex: &sync.Mutex{},
-		AuthTokenTimeout:    0,
+		HttpClient:         &httpClient,
+		Url:                url,
+		BasePath:           basePath,
+		Usr:                usr,
+		Pwd:                pwd,
+		Domain:             domain,
+		Insecure:           insecure,
+		MaxRetries:         DefaultMaxRetries,
+		BackoffMinDelay:    DefaultBackoffMinDelay,
+		BackoffMaxDelay:    DefaultBackoffMaxDelay,
+		BackoffDelayFactor: DefaultBackoffDelayFactor,
+		Logger:             slog.New(slog.DiscardHandler),
+		Redactor:           NewRedactor(),
+		Auth:               NewPasswordAuth(),
+		limits:             &limits{},
+		nodes:              &nodePool{},
 	}
 
 	for _, mod := range mods {
 		mod(&client)
 	}
 
+	// Tokens obtained from one node are rejected by the others
+	if client.nodes.localAuth && client.nodes.strategy == RoundRobin {
+		return client, errors.New("NodeLocalAuth cannot be combined with the RoundRobin node strategy")
+	}
+
 	return client, nil
 }
 
@@ -158,7 +173,7 @@ func BackoffDelayFactor(x float64) func(*Client) {
 
 // NewReq creates a new Req request for this client.
 func (client Client) NewReq(method, uri string, body io.Reader, mods ...func(*Req)) Req {
-	httpReq, _ := http.NewRequest(method, client.Url+uri, body)
+	httpReq, _ := http.NewRequest(method, client.baseUrl()+uri, body)
 	httpReq.Header.Add("Content-Type", "application/json")
 	req := Req{
 		HttpReq:    httpReq,
@@ -170,118 +185,189 @@ func (client Client) NewReq(method, uri string, body io.Reader, mods ...func(*Re
 	return req
 }
 
+// baseUrl returns the URL of the active cluster node.
+func (client Client) baseUrl() string {
+	if client.nodes == nil {
+		return client.Url
+	}
+	return client.nodes.active(client.Url)
+}
+
 // Do makes a request and returns the GJSON result.
 // Requests for Do are built ouside of the client, e.g.
 //
 //	req := client.NewReq("GET", "/appcenter/cisco/ndfc/api/v1/lan-fabric/rest/control/fabrics", nil)
 //	res, _ := client.Do(req)
+//
+// The request is bound to the context set with the Context modifier, if any.
 func (client *Client) Do(req Req) (Res, error) {
-	var res Res
-	defer log.Printf("[DEBUG] Exit from Do method")
-	bodyBytes, err := client.doReq(req)
+	res, err := client.DoFull(req)
+	return res.Res, err
+}
+
+// DoFull makes a request and returns the GJSON result together with the HTTP status code,
+// response headers, the number of attempts and the total duration, e.g.
+//
+//	res, _ := client.DoFull(req)
+//	if res.StatusCode == 201 {
+//		println(res.Header.Get("Location"))
+//	}
+func (client *Client) DoFull(req Req) (Response, error) {
+	res, err := client.doReq(req)
 	// Look for response message in case of error also
-	if len(bodyBytes) > 0 {
-		if !json.Valid(bodyBytes) {
-			res = Res(gjson.Parse(`{"response": "` + string(bodyBytes) + `"}`))
+	if len(res.Body) > 0 {
+		if !json.Valid(res.Body) {
+			res.Res = Res(gjson.Parse(`{"response": "` + string(res.Body) + `"}`))
 		} else {
-			res = Res(gjson.ParseBytes(bodyBytes))
+			res.Res = Res(gjson.ParseBytes(res.Body))
 		}
 	}
-	if err != nil {
-		return res, err
-	}
-	if req.LogPayload {
-		log.Printf("[DEBUG] HTTP Response: %s", res)
-	}
-	return res, nil
+	return res, err
 }
 
 // DoRaw makes a request and returns the raw response (bytes).
 func (client *Client) DoRaw(req Req) ([]byte, error) {
-	defer log.Printf("[DEBUG] Exit from DoRaw method")
-	bodyBytes, err := client.doReq(req)
-	if err != nil {
-		return bodyBytes, err
-	}
-	if req.LogPayload {
-		log.Printf("[DEBUG] HTTP Response: %s", string(bodyBytes))
-	}
-	return bodyBytes, nil
+	res, err := client.doReq(req)
+	return res.Body, err
 }
 
-func (client *Client) doReq(req Req) ([]byte, error) {
+func (client *Client) doReq(req Req) (res Response, err error) {
 	// retain the request body across multiple attempts
 	var body []byte
 	if req.HttpReq.Body != nil {
 		body, _ = io.ReadAll(req.HttpReq.Body)
 	}
-	var bodyBytes []byte
-	defer log.Printf("[DEBUG] Exit from doReq method")
+	ctx := req.HttpReq.Context()
+	logger := client.Logger.With("method", req.HttpReq.Method, "path", req.HttpReq.URL.Path)
+	start := time.Now()
+	defer func() {
+		res.Duration = time.Since(start)
+	}()
+	var delay time.Duration
+	failovers := 0
 	for attempts := 0; ; attempts++ {
-		// Set Authorization header inside loop to pick up refreshed tokens after re-authentication
-		req.HttpReq.Header.Set("Authorization", "Bearer "+client.Token)
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		res.Attempts++
+		attemptLogger := logger.With("attempt", res.Attempts)
+		node := client.nodes.pick(client.Url)
+		if client.nodes.size() > 1 {
+			setNode(req.HttpReq, node)
+			attemptLogger = attemptLogger.With("node", node)
+		}
+		// Apply credentials inside loop to pick up refreshed tokens after re-authentication
+		if err := client.Auth.Apply(req.HttpReq); err != nil {
+			return res, err
+		}
 		req.HttpReq.Body = io.NopCloser(bytes.NewBuffer(body))
-		if req.LogPayload {
-			log.Printf("[DEBUG] HTTP Request: %s, |%s|, |%s|", req.HttpReq.Method, req.HttpReq.URL, req.HttpReq.Body)
+		// Payloads are only redacted if they are actually logged
+		if req.LogPayload && attemptLogger.Enabled(ctx, slog.LevelDebug) {
+			attemptLogger.DebugContext(ctx, "HTTP request", "headers", client.Redactor.RedactHeader(req.HttpReq.Header), "payload", client.Redactor.Redact(body))
 		} else {
-			log.Printf("[DEBUG] HTTP Request: %s, %s", req.HttpReq.Method, req.HttpReq.URL)
+			attemptLogger.DebugContext(ctx, "HTTP request")
 		}
 
+		release, err := client.limits.acquire(ctx, client, req.HttpReq.Method, req.HttpReq.URL.Path)
+		if err != nil {
+			return res, err
+		}
+		// Checked after waiting for the limits, so that a half-open probe is sent right away
+		if err := client.breaker.allow(); err != nil {
+			release()
+			attemptLogger.WarnContext(ctx, "Circuit breaker open, request rejected")
+			return res, err
+		}
+		attemptStart := time.Now()
 		httpRes, err := client.HttpClient.Do(req.HttpReq)
 		if err != nil {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] HTTP Connection error occured: %+v", err)
-				return nil, err
+			release()
+			if ctx.Err() != nil {
+				// Canceled by the caller, which says nothing about the health of Nexus Dashboard
+				client.breaker.abort()
 			} else {
-				log.Printf("[ERROR] HTTP Connection failed: %q, retries: %v", err, attempts)
+				client.breaker.record(false)
+			}
+			attemptLogger = attemptLogger.With("duration", time.Since(attemptStart), "error", err)
+			if failovers < client.nodes.size()-1 && client.failover(ctx, &req, node, err) {
+				// Failing over to another node does not count as retry
+				failovers++
+				attempts--
+				attemptLogger.WarnContext(ctx, "HTTP connection failed, failing over to next node")
 				continue
 			}
+			var ok bool
+			if delay, ok = client.retry(ctx, RetryAttempt{Req: &req, Err: err, Attempt: attempts, LastDelay: delay}); !ok {
+				attemptLogger.ErrorContext(ctx, "HTTP connection error occurred")
+				return res, err
+			}
+			attemptLogger.WarnContext(ctx, "HTTP connection failed, retrying")
+			continue
 		}
+		res.HttpRes = httpRes
+		res.StatusCode = httpRes.StatusCode
+		res.Header = httpRes.Header
 
 		defer httpRes.Body.Close()
-		bodyBytes, err = io.ReadAll(httpRes.Body)
+		res.Body, err = io.ReadAll(httpRes.Body)
+		release()
+		if err != nil && ctx.Err() != nil {
+			client.breaker.abort()
+		} else {
+			client.breaker.record(err == nil && httpRes.StatusCode < 500)
+		}
+		attemptLogger = attemptLogger.With("status", httpRes.StatusCode, "duration", time.Since(attemptStart))
+		if id := requestID(req.HttpReq, httpRes); id != "" {
+			attemptLogger = attemptLogger.With("request_id", id)
+		}
 		if err != nil {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] Cannot decode response body: %+v", err)
-				return nil, err
-			} else {
-				log.Printf("[ERROR] Cannot decode response body: %s, retries: %v", err, attempts)
-				continue
+			res.Body = nil
+			attemptLogger = attemptLogger.With("error", err)
+			var ok bool
+			if delay, ok = client.retry(ctx, RetryAttempt{Req: &req, Response: httpRes, Err: err, Attempt: attempts, LastDelay: delay}); !ok {
+				attemptLogger.ErrorContext(ctx, "Cannot decode response body")
+				return res, err
 			}
+			attemptLogger.WarnContext(ctx, "Cannot decode response body, retrying")
+			continue
 		}
 
+		if req.LogPayload && attemptLogger.Enabled(ctx, slog.LevelDebug) {
+			attemptLogger.DebugContext(ctx, "HTTP response", "payload", client.Redactor.Redact(res.Body))
+		} else {
+			attemptLogger.DebugContext(ctx, "HTTP response")
+		}
 		if httpRes.StatusCode >= 200 && httpRes.StatusCode <= 299 {
 			break
-		} else {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-				return bodyBytes, fmt.Errorf("HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-			} else if httpRes.StatusCode == 408 || (httpRes.StatusCode >= 501 && httpRes.StatusCode <= 599) {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v, Retries: %v", httpRes.StatusCode, attempts)
-				continue
-			} else if httpRes.StatusCode == 401 && strings.Contains(string(bodyBytes), "token has expired") {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v, Retries: %v", httpRes.StatusCode, attempts)
-				client.Token = ""
-				err := client.Authenticate()
-				if err != nil {
-					log.Printf("[ERROR] Authentication failed: StatusCode %v, Retries: %v", httpRes.StatusCode, attempts)
-					return bodyBytes, fmt.Errorf("HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-				}
-			} else {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-				return bodyBytes, fmt.Errorf("HTTP Request failed: StatusCode %v", httpRes.StatusCode)
+		}
+		if httpRes.StatusCode == 401 && attempts < client.MaxRetries && client.Auth.Invalidate(req.HttpReq, res.Body) {
+			attemptLogger.WarnContext(ctx, "HTTP request failed with invalid credentials, retrying")
+			err := client.AuthenticateContext(ctx)
+			if err != nil {
+				attemptLogger.ErrorContext(ctx, "Authentication failed", "error", err)
+				return res, newAPIError(req.HttpReq, httpRes.StatusCode, res.Body, attempts > 0)
+			}
+			continue
+		}
+		var ok bool
+		if delay, ok = client.retry(ctx, RetryAttempt{Req: &req, Response: httpRes, Attempt: attempts, LastDelay: delay}); !ok {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
 			}
+			attemptLogger.ErrorContext(ctx, "HTTP request failed")
+			return res, newAPIError(req.HttpReq, httpRes.StatusCode, res.Body, attempts > 0)
 		}
+		attemptLogger.WarnContext(ctx, "HTTP request failed, retrying")
 	}
 
-	return bodyBytes, nil
+	return res, nil
 }
 
 // Get makes a GET request and returns a GJSON result.
 // Results will be the raw data structure as returned by Nexus Dashboard
 func (client *Client) Get(path string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("GET", client.BasePath+path, nil, mods...)
-	err := client.Authenticate()
+	err := client.AuthenticateContext(req.HttpReq.Context())
 	if err != nil {
 		return Res{}, err
 	}
@@ -292,7 +378,7 @@ func (client *Client) Get(path string, mods ...func(*Req)) (Res, error) {
 // Results will be the raw data structure as returned by Nexus Dashboard
 func (client *Client) GetRawJson(path string, mods ...func(*Req)) ([]byte, error) {
 	req := client.NewReq("GET", client.BasePath+path, nil, mods...)
-	err := client.Authenticate()
+	err := client.AuthenticateContext(req.HttpReq.Context())
 	if err != nil {
 		return nil, err
 	}
@@ -301,9 +387,14 @@ func (client *Client) GetRawJson(path string, mods ...func(*Req)) ([]byte, error
 
 // Delete makes a DELETE request and returns a GJSON result.
 // Hint: Use the Body struct to easily create DELETE body data.
-func (client *Client) Delete(path string, data string, mods ...func(*Req)) (Res, error) {
-	req := client.NewReq("DELETE", client.BasePath+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
+// Data can be a JSON string or byte slice, a Body, a PatchBuilder, a Res or any other value, which is marshaled to JSON.
+func (client *Client) Delete(path string, data any, mods ...func(*Req)) (Res, error) {
+	body, err := requestBody(data)
+	if err != nil {
+		return Res{}, err
+	}
+	req := client.NewReq("DELETE", client.BasePath+path, body, mods...)
+	err = client.AuthenticateContext(req.HttpReq.Context())
 	if err != nil {
 		return Res{}, err
 	}
@@ -312,9 +403,14 @@ func (client *Client) Delete(path string, data string, mods ...func(*Req)) (Res,
 
 // Post makes a POST request and returns a GJSON result.
 // Hint: Use the Body struct to easily create POST body data.
-func (client *Client) Post(path, data string, mods ...func(*Req)) (Res, error) {
-	req := client.NewReq("POST", client.BasePath+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
+// Data can be a JSON string or byte slice, a Body, a PatchBuilder, a Res or any other value, which is marshaled to JSON.
+func (client *Client) Post(path string, data any, mods ...func(*Req)) (Res, error) {
+	body, err := requestBody(data)
+	if err != nil {
+		return Res{}, err
+	}
+	req := client.NewReq("POST", client.BasePath+path, body, mods...)
+	err = client.AuthenticateContext(req.HttpReq.Context())
 	if err != nil {
 		return Res{}, err
 	}
@@ -323,9 +419,47 @@ func (client *Client) Post(path, data string, mods ...func(*Req)) (Res, error) {
 
 // Put makes a PUT request and returns a GJSON result.
 // Hint: Use the Body struct to easily create PUT body data.
-func (client *Client) Put(path, data string, mods ...func(*Req)) (Res, error) {
-	req := client.NewReq("PUT", client.BasePath+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
+// Data can be a JSON string or byte slice, a Body, a PatchBuilder, a Res or any other value, which is marshaled to JSON.
+func (client *Client) Put(path string, data any, mods ...func(*Req)) (Res, error) {
+	body, err := requestBody(data)
+	if err != nil {
+		return Res{}, err
+	}
+	req := client.NewReq("PUT", client.BasePath+path, body, mods...)
+	err = client.AuthenticateContext(req.HttpReq.Context())
+	if err != nil {
+		return Res{}, err
+	}
+	return client.Do(req)
+}
+
+// Patch makes a PATCH request and returns a GJSON result.
+// Data is handled like for Post. Use the JSONPatch modifier to send an RFC 6902 JSON Patch document, e.g.
+//
+//	client.Patch("/mso/api/v1/schemas/"+id, `[{"op": "replace", "path": "/displayName", "value": "ABC"}]`, nd.JSONPatch)
+func (client *Client) Patch(path string, data any, mods ...func(*Req)) (Res, error) {
+	return client.Request("PATCH", path, data, mods...)
+}
+
+// Head makes a HEAD request and returns the response without body, e.g. to check whether an object exists.
+func (client *Client) Head(path string, mods ...func(*Req)) (Response, error) {
+	req := client.NewReq("HEAD", client.BasePath+path, nil, mods...)
+	err := client.AuthenticateContext(req.HttpReq.Context())
+	if err != nil {
+		return Response{}, err
+	}
+	return client.DoFull(req)
+}
+
+// Request makes a request with an arbitrary method and returns a GJSON result.
+// Empty data sends the request without body.
+func (client *Client) Request(method, path string, data any, mods ...func(*Req)) (Res, error) {
+	body, err := requestBody(data)
+	if err != nil {
+		return Res{}, err
+	}
+	req := client.NewReq(method, client.BasePath+path, body, mods...)
+	err = client.AuthenticateContext(req.HttpReq.Context())
 	if err != nil {
 		return Res{}, err
 	}
@@ -334,103 +468,105 @@ func (client *Client) Put(path, data string, mods ...func(*Req)) (Res, error) {
 
 // Login authenticates to the Nexus Dashboard instance.
 func (client *Client) Login() error {
-	body := ""
-	body, _ = sjson.Set(body, "userName", client.Usr)
-	body, _ = sjson.Set(body, "userPasswd", client.Pwd)
-	body, _ = sjson.Set(body, "domain", client.Domain)
-	req := client.NewReq("POST", "/login", strings.NewReader(body), NoLogPayload)
-	log.Printf("[TRACE] Client Login: starting http request")
-	httpRes, err := client.HttpClient.Do(req.HttpReq)
-	if err != nil {
-		log.Printf("[ERROR] Client Login: HTTP request failed - %v", err)
-		return err
-	}
-	defer httpRes.Body.Close()
-	if httpRes.StatusCode != 200 {
-		log.Printf("[ERROR] Authentication failed: StatusCode %v", httpRes.StatusCode)
-		return fmt.Errorf("Authentication failed")
-	}
-	bodyBytes, _ := io.ReadAll(httpRes.Body)
-	res := Res(gjson.ParseBytes(bodyBytes))
-	token := res.Get("token").String()
-	if token == "" {
-		log.Printf("[ERROR] Token retrieval failed: no token in payload")
-		return fmt.Errorf("Authentication failed")
-	}
-	client.Token = token
-	client.AuthTimeStamp = time.Now()
-	log.Printf("[DEBUG] Authentication successful")
+	return client.LoginContext(context.Background())
+}
+
+// LoginContext authenticates to the Nexus Dashboard instance.
+// The login request is aborted when ctx is canceled.
+// Authenticators other than PasswordAuth are refreshed instead.
+func (client *Client) LoginContext(ctx context.Context) error {
+	if auth, ok := client.Auth.(*PasswordAuth); ok {
+		return auth.Login(ctx, client)
+	}
+	return client.Auth.Refresh(ctx, client)
+}
+
+// Logout ends the authentication session on Nexus Dashboard.
+func (client *Client) Logout() error {
+	return client.LogoutContext(context.Background())
+}
+
+// LogoutContext ends the authentication session on Nexus Dashboard.
+// Authenticators which do not support logging out, e.g. APIKeyAuth, are left untouched.
+// A subsequent request logs in again.
+func (client *Client) LogoutContext(ctx context.Context) error {
+	if auth, ok := client.Auth.(LogoutAuthenticator); ok {
+		return auth.Logout(ctx, client)
+	}
 	return nil
 }
 
-func (client *Client) checkAndFillTokenTimeout() {
-	req := client.NewReq("GET", "/api/config/dn/apigwcfg/default", nil, NoLogPayload)
-	result, err := client.Do(req)
-	if err != nil {
-		log.Printf("[ERROR] Get API Config: %v", err)
-		return
-	}
-	/* Response Format
-			{
-			"config": {
-				"idle_session_timeout_sec": 3600,
-				"jwt_session_timeout_sec": 1200,
-				"log_level": "info"
-			},
-	    }
-	*/
-	tokenTimeout := result.Get("config.jwt_session_timeout_sec").Int()
-	if tokenTimeout > 0 {
-		client.AuthTokenTimeout = (time.Duration(tokenTimeout/2) * time.Second)
-		log.Printf("[INFO] Token timeout set to %v", client.AuthTokenTimeout)
-		return
-	}
-	client.AuthTokenTimeout = 2 * time.Minute
-	log.Printf("[ERROR] Token timeout could not be read %v, using default value", result)
+// Close logs out and closes idle connections of the HTTP client.
+// The client can still be used afterwards, which requires a new login.
+func (client *Client) Close() error {
+	err := client.Logout()
+	client.HttpClient.CloseIdleConnections()
+	return err
+}
+
+// WithSession calls fn and closes the client afterwards, making sure the session is ended on exit, e.g.
+//
+//	err := client.WithSession(func(client *nd.Client) error {
+//		_, err := client.Get("/lan-fabric/rest/control/fabrics")
+//		return err
+//	})
+func (client *Client) WithSession(fn func(*Client) error) error {
+	err := fn(client)
+	return errors.Join(err, client.Close())
 }
 
 // Login if no token available or token timeout has reached
 func (client *Client) Authenticate() error {
-	var err error
-	log.Printf("[TRACE] Attempting authentication...")
-	client.AuthenticationMutex.Lock()
-	loginNeeded := false
-	if client.Token == "" {
-		log.Printf("[DEBUG] No token available, attempting login...")
-		loginNeeded = true
-	} else if time.Since(client.AuthTimeStamp) > client.AuthTokenTimeout {
-		log.Printf("[DEBUG] Token has expired, attempting login...")
-		loginNeeded = true
-	}
-	if loginNeeded {
-		err = client.Login()
-		client.checkAndFillTokenTimeout()
-	}
-	log.Printf("[TRACE] Authentication complete")
-	client.AuthenticationMutex.Unlock()
-	return err
+	return client.AuthenticateContext(context.Background())
+}
+
+// AuthenticateContext makes sure valid credentials are available,
+// i.e. with password authentication it logs in if no token is available or the token timeout has been reached.
+// The login and token timeout discovery requests are aborted when ctx is canceled.
+// Concurrent calls wait for a single login to complete.
+func (client *Client) AuthenticateContext(ctx context.Context) error {
+	return client.Auth.Refresh(ctx, client)
+}
+
+// Token returns the current authentication token, if the authenticator uses tokens.
+func (client *Client) Token() string {
+	if auth, ok := client.Auth.(interface{ Token() string }); ok {
+		return auth.Token()
+	}
+	return ""
+}
+
+// AuthTimeStamp returns the time the current authentication token was obtained with password authentication.
+func (client *Client) AuthTimeStamp() time.Time {
+	if auth, ok := client.Auth.(*PasswordAuth); ok {
+		return auth.AuthTimeStamp()
+	}
+	return time.Time{}
+}
+
+// AuthTokenTimeout returns the duration after which the authentication token is renewed with password authentication.
+func (client *Client) AuthTokenTimeout() time.Duration {
+	if auth, ok := client.Auth.(*PasswordAuth); ok {
+		return auth.AuthTokenTimeout()
+	}
+	return 0
 }
 
 // Backoff waits following an exponential backoff algorithm
 func (client *Client) Backoff(attempts int) bool {
-	log.Printf("[DEBUG] Begining backoff method: attempts %v on %v", attempts, client.MaxRetries)
-	if attempts >= client.MaxRetries {
-		log.Printf("[DEBUG] Exit from backoff method with return value false")
+	return client.BackoffContext(context.Background(), attempts)
+}
+
+// BackoffContext waits for the delay of the retry policy before the next attempt.
+// It returns false if the maximum number of retries has been reached
+// or without further waiting as soon as ctx is canceled.
+func (client *Client) BackoffContext(ctx context.Context, attempts int) bool {
+	if ctx.Err() != nil {
 		return false
 	}
-
-	minDelay := time.Duration(client.BackoffMinDelay) * time.Second
-	maxDelay := time.Duration(client.BackoffMaxDelay) * time.Second
-
-	min := float64(minDelay)
-	backoff := min * math.Pow(client.BackoffDelayFactor, float64(attempts))
-	if backoff > float64(maxDelay) {
-		backoff = float64(maxDelay)
+	if attempts >= client.MaxRetries {
+		client.Logger.Log(ctx, LevelTrace, "Maximum number of retries reached", "attempts", attempts, "max_retries", client.MaxRetries)
+		return false
 	}
-	backoff = (rand.Float64()/2+0.5)*(backoff-min) + min
-	backoffDuration := time.Duration(backoff)
-	log.Printf("[TRACE] Starting sleeping for %v", backoffDuration.Round(time.Second))
-	time.Sleep(backoffDuration)
-	log.Printf("[DEBUG] Exit from backoff method with return value true")
-	return true
+	return client.sleep(ctx, client.retryPolicy().NextDelay(RetryAttempt{Attempt: attempts}))
 }
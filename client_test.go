@@ -1,9 +1,11 @@
 package nd
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,9 +25,8 @@ func testClient() Client {
 
 func authenticatedTestClient() Client {
 	client := testClient()
-	client.Token = "ABC"
-	client.AuthTimeStamp = time.Now()
-	client.AuthTokenTimeout = 2 * time.Minute
+	client.Auth.(*PasswordAuth).set("ABC", time.Now())
+	client.Auth.(*PasswordAuth).setTimeout(2 * time.Minute)
 	return client
 }
 
@@ -182,6 +183,98 @@ func TestClientPut(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestClientPostData tests the different data types accepted by the Client::Post method.
+func TestClientPostData(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	var err error
+
+	// Body
+	gock.New(testURL).Post("/url").BodyString(`{"a":1}`).Reply(200)
+	_, err = client.Post("/url", Body{}.SetInt("a", 1))
+	assert.NoError(t, err)
+
+	// Struct
+	gock.New(testURL).Post("/url").BodyString(`{"a":true}`).Reply(200)
+	_, err = client.Post("/url", struct {
+		A bool `json:"a"`
+	}{true})
+	assert.NoError(t, err)
+
+	// PatchBuilder
+	gock.New(testURL).Post("/url").BodyString(`[{"op":"remove","path":"/a"}]`).Reply(200)
+	_, err = client.Post("/url", PatchBuilder{}.Remove("/a"))
+	assert.NoError(t, err)
+
+	// Pointers are dereferenced instead of marshaled
+	body := Body{}.SetInt("a", 1)
+	gock.New(testURL).Post("/url").BodyString(`{"a":1}`).Reply(200)
+	_, err = client.Post("/url", &body)
+	assert.NoError(t, err)
+	patch := PatchBuilder{}.Remove("/a")
+	gock.New(testURL).Post("/url").BodyString(`[{"op":"remove","path":"/a"}]`).Reply(200)
+	_, err = client.Post("/url", &patch)
+	assert.NoError(t, err)
+	gock.New(testURL).Post("/url").BodyString(``).Reply(200)
+	_, err = client.Post("/url", (*Body)(nil))
+	assert.NoError(t, err)
+
+	// Body with error is not sent
+	_, err = client.Post("/url", Body{}.SetAny("a", make(chan int)))
+	assert.Error(t, err)
+	_, err = client.Put("/url", Body{}.Set("", "a"))
+	assert.Error(t, err)
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+// TestClientPatch tests the Client::Patch method.
+func TestClientPatch(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	var err error
+
+	// Success
+	gock.New(testURL).Patch("/url").MatchType("application/json-patch+json").BodyString(`[]`).Reply(200)
+	_, err = client.Patch("/url", "[]", JSONPatch)
+	assert.NoError(t, err)
+
+	// Invalid HTTP status code
+	gock.New(testURL).Patch("/url").Reply(405)
+	_, err = client.Patch("/url", "{}")
+	assert.Error(t, err)
+}
+
+// TestClientHead tests the Client::Head method.
+func TestClientHead(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Success
+	gock.New(testURL).Head("/url").Reply(200).SetHeader("X-Test", "a")
+	res, err := client.Head("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "a", res.Header.Get("X-Test"))
+
+	// Not found
+	gock.New(testURL).Head("/url").Reply(404)
+	_, err = client.Head("/url")
+	assert.True(t, IsNotFound(err))
+}
+
+// TestClientRequest tests the Client::Request method.
+func TestClientRequest(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"a": "b"}`)
+	res, err := client.Request("GET", "/url", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", res.Get("a").String())
+}
+
 // TestClientGetRawJson tests the Client::GetRawJson method.
 func TestClientGetRawJson(t *testing.T) {
 	defer gock.Off()
@@ -214,3 +307,110 @@ func TestClientGetRawJson(t *testing.T) {
 	_, err = client.GetRawJson("/url")
 	assert.Error(t, err)
 }
+
+// TestClientContext tests request cancellation with the Context modifier.
+func TestClientContext(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.MaxRetries = 3
+	client.BackoffMinDelay = 60
+	client.BackoffMaxDelay = 60
+
+	// Canceled before the request is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Get("/url", Context(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Deadline expires during backoff
+	gock.New(testURL).Get("/url").Times(4).Reply(503)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.Get("/url", Context(ctx))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// TestClientDoFull tests the Client::DoFull method.
+func TestClientDoFull(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Success
+	gock.New(testURL).Post("/url").Reply(201).SetHeader("Location", "/url/1").BodyString(`{"id": 1}`)
+	res, err := client.DoFull(client.NewReq("POST", "/url", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, "/url/1", res.Header.Get("Location"))
+	assert.Equal(t, int64(1), res.Res.Get("id").Int())
+	assert.Equal(t, 1, res.Attempts)
+	assert.Greater(t, res.Duration, time.Duration(0))
+
+	// Retried request
+	client.MaxRetries = 1
+	client.BackoffMinDelay = 0
+	client.BackoffMaxDelay = 0
+	gock.New(testURL).Get("/url").Reply(503)
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{}`)
+	res, err = client.DoFull(client.NewReq("GET", "/url", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, 2, res.Attempts)
+
+	// Invalid HTTP status code
+	gock.New(testURL).Get("/url").Reply(405).BodyString(`{"message": "fail"}`)
+	res, err = client.DoFull(client.NewReq("GET", "/url", nil))
+	assert.Error(t, err)
+	assert.Equal(t, 405, res.StatusCode)
+	assert.Equal(t, "fail", res.Res.Get("message").String())
+}
+
+// TestClientConcurrentAuthentication tests that concurrent requests with an expired token trigger a single login.
+func TestClientConcurrentAuthentication(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.Auth.(*PasswordAuth).set("OLD", time.Now().Add(-time.Hour))
+
+	gock.New(testURL).Post("/login").Times(1).Reply(200).BodyString(`{"token": "NEW"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Times(1).Reply(200).BodyString(`{"config": {"jwt_session_timeout_sec": 1200}}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer NEW").Times(20).Reply(200)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		copy := client
+		go func() {
+			defer wg.Done()
+			_, err := copy.Get("/url")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, "NEW", client.Token())
+	assert.Equal(t, 10*time.Minute, client.AuthTokenTimeout())
+}
+
+// TestClientTokenExpired tests the re-login after a request was rejected with an expired token.
+func TestClientTokenExpired(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.MaxRetries = 1
+	client.BackoffMinDelay = 0
+	client.BackoffMaxDelay = 0
+
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer ABC").Reply(401).BodyString(`{"message": "token has expired"}`)
+	gock.New(testURL).Post("/login").Reply(200).BodyString(`{"token": "NEW"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer NEW").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, "NEW", client.Token())
+}
@@ -0,0 +1,216 @@
+package nd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// DecodeError is returned if a response cannot be decoded into a Go value.
+type DecodeError struct {
+	// Path is the GJSON path of the value which failed to decode, empty if it is unknown or the whole document.
+	Path string
+	// Err is the underlying encoding/json error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("decoding response failed: %v", e.Err)
+	}
+	return fmt.Sprintf("decoding response failed at %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DoInto makes a request and decodes the JSON response into v, e.g.
+//
+//	var fabrics []Fabric
+//	req := client.NewReq("GET", "/appcenter/cisco/ndfc/api/v1/lan-fabric/rest/control/fabrics", nil)
+//	err := client.DoInto(req, &fabrics)
+//
+// Unknown fields are ignored unless the request is built with the StrictDecode modifier.
+// An empty response leaves v unchanged.
+func (client *Client) DoInto(req Req, v any) error {
+	res, err := client.DoFull(req)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(res.Body)) == 0 {
+		return nil
+	}
+	return decode(res.Body, v, req.StrictDecode)
+}
+
+// GetInto makes a GET request and decodes the JSON response into v.
+func (client *Client) GetInto(path string, v any, mods ...func(*Req)) error {
+	req := client.NewReq("GET", client.BasePath+path, nil, mods...)
+	err := client.AuthenticateContext(req.HttpReq.Context())
+	if err != nil {
+		return err
+	}
+	return client.DoInto(req, v)
+}
+
+// GetAs makes a GET request and returns the JSON response decoded into a value of type T, e.g.
+//
+//	fabrics, err := nd.GetAs[[]Fabric](&client, "/lan-fabric/rest/control/fabrics", nd.StrictDecode)
+func GetAs[T any](client *Client, path string, mods ...func(*Req)) (T, error) {
+	var v T
+	err := client.GetInto(path, &v, mods...)
+	return v, err
+}
+
+// decode decodes data into v and determines the path of the offending value on errors.
+func decode(data []byte, v any, strict bool) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	err := dec.Decode(v)
+	if err == nil {
+		return nil
+	}
+	decodeErr := &DecodeError{Err: err}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		decodeErr.Path = typeErrorField(gjson.ParseBytes(data), strings.Split(typeErr.Field, "."), "")
+		if decodeErr.Path == "" {
+			decodeErr.Path = typeErr.Field
+		}
+	} else if strict && strings.HasPrefix(err.Error(), "json: unknown field ") {
+		decodeErr.Path = unknownField(reflect.TypeOf(v), gjson.ParseBytes(data), "")
+	}
+	return decodeErr
+}
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// unknownField returns the path of the first field in res which does not exist in type t.
+func unknownField(t reflect.Type, res Res, path string) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return ""
+	}
+	found := ""
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := jsonFields(t)
+		res.ForEach(func(key, value Res) bool {
+			fieldPath := joinPath(path, key.String())
+			fieldType, ok := fields[key.String()]
+			if !ok {
+				for name, f := range fields {
+					if strings.EqualFold(name, key.String()) {
+						fieldType, ok = f, true
+						break
+					}
+				}
+			}
+			if !ok {
+				found = fieldPath
+			} else {
+				found = unknownField(fieldType, value, fieldPath)
+			}
+			return found == ""
+		})
+	case reflect.Slice, reflect.Array:
+		for i, item := range res.Array() {
+			if found = unknownField(t.Elem(), item, joinPath(path, strconv.Itoa(i))); found != "" {
+				break
+			}
+		}
+	case reflect.Map:
+		res.ForEach(func(key, value Res) bool {
+			found = unknownField(t.Elem(), value, joinPath(path, key.String()))
+			return found == ""
+		})
+	}
+	return found
+}
+
+// jsonFields returns the types of the fields of a struct type by JSON name, including fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFields(fieldType) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = embeddedType
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// typeErrorField returns the GJSON path of the dotted field of a type error, whose keys may contain dots themselves,
+// by matching the segments against the keys in res. It returns an empty string if there is no match.
+func typeErrorField(res Res, segments []string, path string) string {
+	if len(segments) == 0 || segments[0] == "" {
+		return path
+	}
+	for n := 1; n <= len(segments); n++ {
+		key := strings.Join(segments[:n], ".")
+		var child Res
+		if res.IsArray() {
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(res.Array()) {
+				child = res.Array()[i]
+			}
+		} else {
+			res.ForEach(func(k, value Res) bool {
+				if k.String() == key {
+					child = value
+					return false
+				}
+				return true
+			})
+		}
+		if !child.Exists() {
+			continue
+		}
+		if found := typeErrorField(child, segments[n:], joinPath(path, key)); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// joinPath appends a key to a GJSON path, escaping special characters.
+func joinPath(path, key string) string {
+	key = strings.NewReplacer(".", `\.`, "*", `\*`, "?", `\?`).Replace(key)
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
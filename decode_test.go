@@ -0,0 +1,85 @@
+package nd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type testSwitch struct {
+	Serial string `json:"serialNumber"`
+	Ports  []struct {
+		Name string `json:"name"`
+		Vlan int    `json:"vlan"`
+	} `json:"ports"`
+}
+
+type testFabric struct {
+	Name     string                `json:"fabricName"`
+	Switches map[string]testSwitch `json:"switches"`
+}
+
+// TestGetAs tests the GetAs function.
+func TestGetAs(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Success
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"fabricName": "a", "id": 1, "switches": {"leaf1": {"serialNumber": "ABC"}}}`)
+	fabric, err := GetAs[testFabric](&client, "/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", fabric.Name)
+	assert.Equal(t, "ABC", fabric.Switches["leaf1"].Serial)
+
+	// Strict decoding
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"fabricName": "a", "switches": {"leaf.1": {"serialNumber": "ABC", "ports": [{"name": "e1/1"}, {"name": "e1/2", "mode": "trunk"}]}}}`)
+	_, err = GetAs[testFabric](&client, "/url", StrictDecode)
+	decodeErr := &DecodeError{}
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, `switches.leaf\.1.ports.1.mode`, decodeErr.Path)
+
+	// Type error
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"switches": {"leaf1": {"ports": [{"vlan": "10"}]}}}`)
+	_, err = GetAs[testFabric](&client, "/url")
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "switches.leaf1.ports.0.vlan", decodeErr.Path)
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"switches": {"leaf.1": {"ports": [{"vlan": "10"}]}}}`)
+	_, err = GetAs[testFabric](&client, "/url")
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, `switches.leaf\.1.ports.0.vlan`, decodeErr.Path)
+
+	// Invalid JSON
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"fabricName": `)
+	_, err = GetAs[testFabric](&client, "/url")
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "", decodeErr.Path)
+
+	// HTTP error
+	gock.New(testURL).Get("/url").Reply(404)
+	_, err = GetAs[testFabric](&client, "/url")
+	assert.True(t, IsNotFound(err))
+}
+
+// TestClientDoInto tests the Client::DoInto method.
+func TestClientDoInto(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`[{"serialNumber": "ABC", "model": "N9K"}]`)
+	var switches []testSwitch
+	err := client.DoInto(client.NewReq("GET", "/url", nil), &switches)
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC", switches[0].Serial)
+
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`[{"serialNumber": "ABC", "model": "N9K"}]`)
+	err = client.DoInto(client.NewReq("GET", "/url", nil, StrictDecode), &switches)
+	assert.ErrorContains(t, err, "decoding response failed at 0.model")
+
+	// Empty response
+	gock.New(testURL).Get("/url").Reply(200)
+	err = client.DoInto(client.NewReq("GET", "/url", nil), &switches)
+	assert.NoError(t, err)
+	assert.Len(t, switches, 1)
+}
@@ -0,0 +1,144 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// APIError is returned when Nexus Dashboard responds with a non-2xx HTTP status code.
+// Use errors.As to access the details:
+//
+//	var apiErr *nd.APIError
+//	if errors.As(err, &apiErr) {
+//		fmt.Println(apiErr.StatusCode, apiErr.Message)
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code of the last attempt.
+	StatusCode int
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the request URL.
+	URL string
+	// Body is the raw response body.
+	Body []byte
+	// Message is the error message reported by Nexus Dashboard, if any.
+	Message string
+	// Code is the error code reported by Nexus Dashboard, if any.
+	Code string
+	// Messages are the individual error messages reported by Nexus Dashboard, if any.
+	Messages []string
+	// Retried indicates whether the request was retried before giving up.
+	Retried bool
+}
+
+func newAPIError(req *http.Request, statusCode int, body []byte, retried bool) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Body:       body,
+		Retried:    retried,
+	}
+	if req != nil {
+		e.Method = req.Method
+		e.URL = req.URL.String()
+	}
+	if !gjson.ValidBytes(body) {
+		return e
+	}
+	/* Known error formats
+	NDFC:
+		{"timestamp": 1700000000000, "status": 400, "error": "Bad Request", "message": "Invalid fabric", "path": "/rest/control/fabrics"}
+	Nexus Dashboard:
+		{"code": 400, "messages": [{"code": 400, "severity": "ERROR", "message": "Invalid input"}]}
+		{"error": {"code": "400", "message": "Invalid input", "messages": ["..."]}}
+	*/
+	res := gjson.ParseBytes(body)
+	e.Message = res.Get("message").String()
+	e.Code = res.Get("code").String()
+	errRes := res.Get("error")
+	if errRes.IsObject() {
+		if e.Message == "" {
+			e.Message = errRes.Get("message").String()
+		}
+		if e.Code == "" {
+			e.Code = errRes.Get("code").String()
+		}
+		e.Messages = append(e.Messages, errorMessages(errRes.Get("messages"))...)
+	} else if e.Message == "" {
+		e.Message = errRes.String()
+	}
+	e.Messages = append(e.Messages, errorMessages(res.Get("messages"))...)
+	if e.Message == "" && len(e.Messages) > 0 {
+		e.Message = e.Messages[0]
+	}
+	return e
+}
+
+func errorMessages(res gjson.Result) []string {
+	var messages []string
+	for _, m := range res.Array() {
+		if m.IsObject() {
+			m = m.Get("message")
+		}
+		if m.String() != "" {
+			messages = append(messages, m.String())
+		}
+	}
+	return messages
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("HTTP Request failed: StatusCode %v", e.StatusCode)
+	if e.Method != "" {
+		msg += fmt.Sprintf(" (%s %s)", e.Method, e.URL)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if len(e.Messages) > 1 {
+		msg += " [" + strings.Join(e.Messages, "; ") + "]"
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is an APIError with HTTP status 404.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with HTTP status 409, e.g. when an object already exists.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsUnauthorized reports whether err is an APIError with HTTP status 401.
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsTimeout reports whether err is caused by a timeout, either on the client side
+// (deadline exceeded, network timeout) or reported by the server (HTTP status 408 or 504).
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return hasStatusCode(err, http.StatusRequestTimeout) || hasStatusCode(err, http.StatusGatewayTimeout)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
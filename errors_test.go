@@ -0,0 +1,68 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestAPIError tests the APIError type returned by failed requests.
+func TestAPIError(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// NDFC error format
+	gock.New(testURL).Get("/url").Reply(404).BodyString(`{"status": 404, "error": "Not Found", "message": "Fabric not found"}`)
+	_, err := client.Get("/url")
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 404, apiErr.StatusCode)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Contains(t, apiErr.URL, "/url")
+	assert.Equal(t, "Fabric not found", apiErr.Message)
+	assert.False(t, apiErr.Retried)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+
+	// Nexus Dashboard error format
+	gock.New(testURL).Post("/url").Reply(409).BodyString(`{"code": 409, "messages": [{"code": 409, "severity": "ERROR", "message": "Object already exists"}]}`)
+	_, err = client.Post("/url", "{}")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "409", apiErr.Code)
+	assert.Equal(t, "Object already exists", apiErr.Message)
+	assert.Equal(t, []string{"Object already exists"}, apiErr.Messages)
+	assert.True(t, IsConflict(err))
+
+	// Nested error object
+	gock.New(testURL).Put("/url").Reply(400).BodyString(`{"error": {"code": "E1", "message": "Invalid input", "messages": ["a", "b"]}}`)
+	_, err = client.Put("/url", "{}")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "E1", apiErr.Code)
+	assert.Equal(t, "Invalid input", apiErr.Message)
+	assert.Equal(t, []string{"a", "b"}, apiErr.Messages)
+
+	// Retried request
+	client.MaxRetries = 1
+	client.BackoffMinDelay = 0
+	client.BackoffMaxDelay = 0
+	gock.New(testURL).Get("/url").Times(2).Reply(504)
+	_, err = client.Get("/url")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, apiErr.Retried)
+	assert.True(t, IsTimeout(err))
+}
+
+// TestErrorHelpers tests the IsNotFound, IsConflict, IsUnauthorized and IsTimeout functions.
+func TestErrorHelpers(t *testing.T) {
+	wrapped := fmt.Errorf("wrapped: %w", &APIError{StatusCode: 401})
+	assert.True(t, IsUnauthorized(wrapped))
+	assert.False(t, IsNotFound(wrapped))
+	assert.True(t, IsTimeout(context.DeadlineExceeded))
+	assert.True(t, IsTimeout(&APIError{StatusCode: 408}))
+	assert.False(t, IsTimeout(nil))
+	assert.False(t, IsNotFound(errors.New("fail")))
+}
@@ -0,0 +1,182 @@
+package nd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the rate of requests sent by the client to rps requests per second,
+// allowing bursts of up to burst requests. The limit is shared by all copies of the client.
+func RateLimit(rps float64, burst int) func(*Client) {
+	return func(client *Client) {
+		client.limits.global = newRateLimiter(rps, burst)
+	}
+}
+
+// MaxConcurrentRequests limits the number of requests in flight to n. The limit is shared by all copies of the client.
+func MaxConcurrentRequests(n int) func(*Client) {
+	return func(client *Client) {
+		client.limits.inFlight = newSemaphore(n)
+	}
+}
+
+// PathRateLimit limits the rate of requests with the given method and path prefix to rps requests per second,
+// allowing bursts of up to burst requests, e.g. to throttle deployments harder than other requests:
+//
+//	nd.PathRateLimit("POST", "/lan-fabric/rest/control/fabrics", 1, 2)
+//
+// An empty method matches all methods. The prefix is matched with and without the BasePath of the client.
+// Path limits apply in addition to the limit set with RateLimit.
+func PathRateLimit(method, prefix string, rps float64, burst int) func(*Client) {
+	return func(client *Client) {
+		client.limits.rules = append(client.limits.rules, limitRule{method: method, prefix: prefix, rate: newRateLimiter(rps, burst)})
+	}
+}
+
+// PathMaxConcurrentRequests limits the number of requests in flight with the given method and path prefix to n.
+// An empty method matches all methods. The prefix is matched with and without the BasePath of the client.
+// Path limits apply in addition to the limit set with MaxConcurrentRequests.
+func PathMaxConcurrentRequests(method, prefix string, n int) func(*Client) {
+	return func(client *Client) {
+		client.limits.rules = append(client.limits.rules, limitRule{method: method, prefix: prefix, inFlight: newSemaphore(n)})
+	}
+}
+
+// limits holds the rate and concurrency limits shared by all copies of a client.
+type limits struct {
+	global   *rateLimiter
+	inFlight semaphore
+	rules    []limitRule
+}
+
+// limitRule is a rate or concurrency limit for requests matching a method and path prefix.
+type limitRule struct {
+	method   string
+	prefix   string
+	rate     *rateLimiter
+	inFlight semaphore
+}
+
+// acquire waits until the request may be sent according to all applicable limits.
+// The returned function must be called once the request has completed.
+func (l *limits) acquire(ctx context.Context, client *Client, method, path string) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	wait := func(rate *rateLimiter, inFlight semaphore) error {
+		if rate != nil {
+			if err := rate.wait(ctx); err != nil {
+				return err
+			}
+		}
+		if inFlight != nil {
+			if err := inFlight.acquire(ctx); err != nil {
+				return err
+			}
+			releases = append(releases, inFlight.release)
+		}
+		return nil
+	}
+	for _, rule := range l.rules {
+		if rule.matches(client, method, path) {
+			if err := wait(rule.rate, rule.inFlight); err != nil {
+				release()
+				return nil, err
+			}
+		}
+	}
+	if err := wait(l.global, l.inFlight); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+func (rule limitRule) matches(client *Client, method, path string) bool {
+	if rule.method != "" && !strings.EqualFold(rule.method, method) {
+		return false
+	}
+	return strings.HasPrefix(path, rule.prefix) || strings.HasPrefix(path, client.BasePath+rule.prefix)
+}
+
+// rateLimiter is a token bucket rate limiter.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token from the bucket, waiting until one is available or ctx is canceled.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// Reserve a token, waiting for the bucket to refill if it is empty
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mutex.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Return the reserved token
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// semaphore limits the number of concurrent requests.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n < 1 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
@@ -0,0 +1,82 @@
+package nd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimiter tests the token bucket rate limiter.
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, limiter.wait(context.Background()))
+	}
+	// Burst of 2, then 2 more at 20 rps
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// Canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.001, 1)
+	assert.NoError(t, limiter.wait(ctx))
+	assert.ErrorIs(t, limiter.wait(ctx), context.Canceled)
+}
+
+// TestLimits tests the rate and concurrency limits of the client.
+func TestLimits(t *testing.T) {
+	client, _ := NewClient(testURL, "/api", "usr", "pwd", "", true,
+		MaxConcurrentRequests(2),
+		PathMaxConcurrentRequests("POST", "/deploy", 1),
+		PathRateLimit("", "/slow", 0.001, 1),
+	)
+	ctx := context.Background()
+
+	// Concurrency limit
+	var inFlight, maxInFlight atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := client.limits.acquire(ctx, &client, "GET", "/api/url")
+			assert.NoError(t, err)
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxInFlight.Load())
+
+	// Path concurrency limit
+	release, err := client.limits.acquire(ctx, &client, "POST", "/api/deploy/1")
+	assert.NoError(t, err)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.limits.acquire(timeoutCtx, &client, "POST", "/api/deploy/2")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	getRelease, err := client.limits.acquire(ctx, &client, "GET", "/api/deploy/2")
+	assert.NoError(t, err)
+	getRelease()
+	release()
+
+	// Path rate limit
+	release, err = client.limits.acquire(ctx, &client, "GET", "/slow")
+	assert.NoError(t, err)
+	release()
+	_, err = client.limits.acquire(timeoutCtx, &client, "GET", "/api/slow")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,37 @@
+package nd
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// LevelTrace is the log level used for detailed tracing of the client internals, e.g. authentication and backoff.
+// It is lower than slog.LevelDebug and thus only logged if the handler is configured accordingly.
+const LevelTrace = slog.LevelDebug - 4
+
+// Logger sets the structured logger used by the client. The default logger discards all records.
+// Requests are logged with the method, path, attempt, status, duration and request ID as attributes, e.g.
+//
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	client, _ := nd.NewClient("https://10.1.1.1", "/appcenter/cisco/ndfc/api/v1", "user", "password", "", true, nd.Logger(logger))
+//
+// Request and response payloads are logged at debug level unless the NoLogPayload modifier is used.
+func Logger(logger *slog.Logger) func(*Client) {
+	return func(client *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		client.Logger = logger
+	}
+}
+
+// requestID returns the request ID of a request, if set by the caller or returned by Nexus Dashboard.
+func requestID(httpReq *http.Request, httpRes *http.Response) string {
+	if id := httpReq.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	if httpRes != nil {
+		return httpRes.Header.Get("X-Request-Id")
+	}
+	return ""
+}
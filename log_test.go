@@ -0,0 +1,43 @@
+package nd
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestLogger tests the Logger modifier.
+func TestLogger(t *testing.T) {
+	defer gock.Off()
+	var buf bytes.Buffer
+	client := authenticatedTestClient()
+	Logger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))(&client)
+
+	// Payloads are logged with request attributes
+	gock.New(testURL).Post("/url").Reply(200).SetHeader("X-Request-Id", "42").BodyString(`{"result": "ok"}`)
+	_, err := client.Post("/url", `{"name": "abc"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"method":"POST"`)
+	assert.Contains(t, buf.String(), `"path":"//url"`)
+	assert.Contains(t, buf.String(), `"attempt":1`)
+	assert.Contains(t, buf.String(), `"status":200`)
+	assert.Contains(t, buf.String(), `"request_id":"42"`)
+	assert.Contains(t, buf.String(), `"duration"`)
+	assert.Contains(t, buf.String(), `abc`)
+	assert.Contains(t, buf.String(), `ok`)
+
+	// Payloads are not logged with NoLogPayload
+	buf.Reset()
+	gock.New(testURL).Post("/url").Reply(200).BodyString(`{"result": "secret"}`)
+	_, err = client.Post("/url", `{"name": "secret"}`, NoLogPayload)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"method":"POST"`)
+	assert.NotContains(t, buf.String(), "secret")
+
+	// Default logger is silent
+	Logger(nil)(&client)
+	assert.False(t, client.Logger.Enabled(t.Context(), slog.LevelError))
+}
@@ -0,0 +1,134 @@
+package ndfc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// DefaultDeployTimeout is the default maximum duration of a deployment.
+const DefaultDeployTimeout = 30 * time.Minute
+
+// DeployStatus is the deployment outcome of a switch.
+type DeployStatus string
+
+const (
+	// DeployInSync indicates that the switch configuration matches the intended configuration.
+	DeployInSync DeployStatus = "in-sync"
+	// DeployOutOfSync indicates that the switch did not converge before the deadline.
+	DeployOutOfSync DeployStatus = "out-of-sync"
+	// DeployFailed indicates that the deployment failed, e.g. because the switch is unreachable.
+	DeployFailed DeployStatus = "failed"
+)
+
+// DeployResult is the deployment outcome of a single switch.
+type DeployResult struct {
+	SerialNumber string
+	IPAddress    string
+	Name         string
+	Status       DeployStatus
+	// CCStatus is the last observed configuration compliance status, e.g. In-Sync or Out-of-Sync.
+	CCStatus string
+	// Reason describes why the deployment failed or did not converge.
+	Reason string
+}
+
+// Deploy saves the fabric configuration and deploys it to the switches with the given serial numbers,
+// or to all switches of the fabric if none are given. It then polls the configuration compliance status
+// until all switches are in sync or failed, or the Timeout of the service has elapsed or ctx is done, e.g.
+//
+//	fabrics.Timeout = 15 * time.Minute
+//	results, err := fabrics.Deploy(ctx, "fabric1")
+//
+// Switches which failed or did not converge are reported in the results and additionally returned as a joined error.
+func (s *FabricService) Deploy(ctx context.Context, fabric string, serials ...string) ([]DeployResult, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDeployTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if len(serials) == 0 {
+		inventory := NewInventoryService(s.client)
+		switches, err := inventory.Switches(fabric, nd.Context(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for _, sw := range switches {
+			serials = append(serials, sw.SerialNumber)
+		}
+	}
+	_, err := s.client.Post(path(fabricsPath, fabric, "config-save"), nil, nd.Context(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("config-save failed: %w", err)
+	}
+	deployPath := path(fabricsPath, fabric, "config-deploy")
+	if len(serials) > 0 {
+		// NDFC expects a literal comma-separated list of serial numbers
+		escaped := make([]string, len(serials))
+		for i, serial := range serials {
+			escaped[i] = url.PathEscape(serial)
+		}
+		deployPath += "/" + strings.Join(escaped, ",")
+	}
+	_, err = s.client.Post(deployPath, nil, nd.Context(ctx), nd.Query("forceShowRun", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("config-deploy failed: %w", err)
+	}
+
+	results := make([]DeployResult, len(serials))
+	for i, serial := range serials {
+		results[i] = DeployResult{SerialNumber: serial, Status: DeployOutOfSync}
+	}
+	err = waitForSwitches(ctx, s.client, fabric, s.PollInterval, func(switches []Switch) bool {
+		done := true
+		for i := range results {
+			results[i].update(switches)
+			if results[i].Status == DeployOutOfSync {
+				done = false
+			}
+		}
+		return done
+	})
+	var errs []error
+	for _, result := range results {
+		if result.Status != DeployInSync {
+			errs = append(errs, fmt.Errorf("switch %s %s: %s", result.SerialNumber, result.Status, result.Reason))
+		}
+	}
+	return results, errors.Join(append(errs, err)...)
+}
+
+// update sets the deployment status from the current inventory.
+func (r *DeployResult) update(switches []Switch) {
+	for _, sw := range switches {
+		if sw.SerialNumber != r.SerialNumber {
+			continue
+		}
+		r.IPAddress = sw.IPAddress
+		r.Name = sw.Name
+		r.CCStatus = sw.CCStatus
+		switch {
+		case strings.EqualFold(sw.CCStatus, "In-Sync"):
+			r.Status = DeployInSync
+			r.Reason = ""
+		case strings.Contains(strings.ToLower(sw.CCStatus), "fail"):
+			r.Status = DeployFailed
+			r.Reason = sw.CCStatus
+		case strings.EqualFold(sw.Status, "unreachable"):
+			r.Status = DeployFailed
+			r.Reason = "switch unreachable"
+		default:
+			r.Status = DeployOutOfSync
+			r.Reason = sw.CCStatus
+		}
+		return
+	}
+	r.Status = DeployFailed
+	r.Reason = "switch not found in fabric"
+}
@@ -0,0 +1,70 @@
+package ndfc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const fabricPath = testBasePath + "/lan-fabric/rest/control/fabrics/fabric1"
+
+// TestFabricServiceDeploy tests the FabricService::Deploy method.
+func TestFabricServiceDeploy(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+	fabrics.PollInterval = time.Millisecond
+
+	// Selected switches
+	gock.New(testURL).Post(fabricPath + "/config-save").Reply(200).BodyString(`{"status": "Config save is completed"}`)
+	gock.New(testURL).Post(fabricPath+"/config-deploy/9A1B2C3D4E5,9F8E7D6C5B4").MatchParam("forceShowRun", "false").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return req.URL.EscapedPath() == fabricPath+"/config-deploy/9A1B2C3D4E5,9F8E7D6C5B4", nil
+		}).Reply(200)
+	gock.New(testURL).Get(fabricPath + "/inventory/switchesByFabric").Reply(200).BodyString(testData("switches-discovering.json"))
+	gock.New(testURL).Get(fabricPath + "/inventory/switchesByFabric").Reply(200).
+		BodyString(`[{"serialNumber": "9A1B2C3D4E5", "ccStatus": "In-Sync"}, {"serialNumber": "9F8E7D6C5B4", "ccStatus": "In-Sync"}]`)
+	results, err := fabrics.Deploy(context.Background(), "fabric1", "9A1B2C3D4E5", "9F8E7D6C5B4")
+	assert.NoError(t, err)
+	assert.Equal(t, DeployInSync, results[0].Status)
+	assert.Equal(t, DeployInSync, results[1].Status)
+	assert.True(t, gock.IsDone())
+
+	// All switches with timeout
+	gock.New(testURL).Get(fabricPath + "/inventory/switchesByFabric").Reply(200).BodyString(testData("switches-deploy.json"))
+	gock.New(testURL).Post(fabricPath + "/config-save").Reply(200)
+	gock.New(testURL).Post(fabricPath + "/config-deploy").Reply(200)
+	gock.New(testURL).Get(fabricPath + "/inventory/switchesByFabric").Persist().Reply(200).BodyString(testData("switches-deploy.json"))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results, err = fabrics.Deploy(ctx, "fabric1")
+	assert.ErrorContains(t, err, "switch 9F8E7D6C5B4 out-of-sync: Out-of-Sync")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.ErrorContains(t, err, "switch 9C7B5A3E1F0 failed: switch unreachable")
+	assert.Len(t, results, 3)
+	assert.Equal(t, DeployInSync, results[0].Status)
+	assert.Equal(t, DeployOutOfSync, results[1].Status)
+	assert.Equal(t, DeployFailed, results[2].Status)
+	assert.Equal(t, "spine1", results[2].Name)
+	gock.Off()
+
+	// Timeout of the service
+	fabrics.Timeout = 20 * time.Millisecond
+	gock.New(testURL).Post(fabricPath + "/config-save").Reply(200)
+	gock.New(testURL).Post(fabricPath + "/config-deploy/9F8E7D6C5B4").Reply(200)
+	gock.New(testURL).Get(fabricPath + "/inventory/switchesByFabric").Persist().Reply(200).BodyString(testData("switches-deploy.json"))
+	results, err = fabrics.Deploy(context.Background(), "fabric1", "9F8E7D6C5B4")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, DeployOutOfSync, results[0].Status)
+	fabrics.Timeout = 0
+	gock.Off()
+
+	// Config save failure
+	gock.New(testURL).Post(fabricPath + "/config-save").Reply(500).BodyString(`{"message": "Fabric is locked"}`)
+	_, err = fabrics.Deploy(context.Background(), "fabric1", "9A1B2C3D4E5")
+	assert.ErrorContains(t, err, "config-save failed")
+}
@@ -0,0 +1,106 @@
+package ndfc
+
+import (
+	"fmt"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+const fabricsPath = "/lan-fabric/rest/control/fabrics"
+
+// Fabric is an NDFC fabric.
+type Fabric struct {
+	ID              int64             `json:"id"`
+	FabricID        string            `json:"fabricId"`
+	Name            string            `json:"fabricName"`
+	Type            string            `json:"fabricType"`
+	Technology      string            `json:"fabricTechnology"`
+	TemplateName    string            `json:"templateName"`
+	ProvisionMode   string            `json:"provisionMode"`
+	DeviceType      string            `json:"deviceType"`
+	ReplicationMode string            `json:"replicationMode"`
+	OperStatus      string            `json:"operStatus"`
+	ASN             string            `json:"asn"`
+	SiteID          string            `json:"siteId"`
+	NvPairs         map[string]string `json:"nvPairs"`
+}
+
+// Settings decodes the nvPairs of the fabric into a template, e.g.
+//
+//	var settings ndfc.EasyFabric
+//	err := fabric.Settings(&settings)
+func (f Fabric) Settings(template Template) error {
+	if template.TemplateName() != f.TemplateName {
+		return fmt.Errorf("fabric %s uses template %s, not %s", f.Name, f.TemplateName, template.TemplateName())
+	}
+	return DecodeNvPairs(f.NvPairs, template)
+}
+
+// FabricService manages NDFC fabrics.
+type FabricService struct {
+	client *nd.Client
+	// PollInterval is the interval between two status requests while deploying, DefaultPollInterval if zero.
+	PollInterval time.Duration
+	// Timeout is the maximum duration of a deployment, DefaultDeployTimeout if zero.
+	Timeout time.Duration
+}
+
+// NewFabricService creates a new FabricService using the client.
+func NewFabricService(client *nd.Client) *FabricService {
+	return &FabricService{client: client}
+}
+
+// List returns all fabrics.
+func (s *FabricService) List(mods ...func(*nd.Req)) ([]Fabric, error) {
+	return nd.GetAs[[]Fabric](s.client, fabricsPath, mods...)
+}
+
+// Get returns a fabric by name. A missing fabric results in an error matched by nd.IsNotFound.
+func (s *FabricService) Get(name string, mods ...func(*nd.Req)) (Fabric, error) {
+	return nd.GetAs[Fabric](s.client, path(fabricsPath, name), mods...)
+}
+
+// Create creates a fabric with the settings of a template, e.g.
+//
+//	fabric, err := fabrics.Create("fabric1", &ndfc.EasyFabric{BgpAs: "65001"})
+//
+// The FABRIC_NAME setting defaults to name.
+func (s *FabricService) Create(name string, template Template, mods ...func(*nd.Req)) (Fabric, error) {
+	return s.save("POST", name, template, mods...)
+}
+
+// Update replaces the settings of a fabric with the settings of a template.
+// Settings are best updated by modifying the settings of an existing fabric, e.g.
+//
+//	fabric, _ := fabrics.Get("fabric1")
+//	var settings ndfc.EasyFabric
+//	fabric.Settings(&settings)
+//	settings.EnableNxapi = ndfc.Bool(false)
+//	fabric, err := fabrics.Update("fabric1", &settings)
+func (s *FabricService) Update(name string, template Template, mods ...func(*nd.Req)) (Fabric, error) {
+	return s.save("PUT", name, template, mods...)
+}
+
+// Delete deletes a fabric.
+func (s *FabricService) Delete(name string, mods ...func(*nd.Req)) error {
+	_, err := s.client.Delete(path(fabricsPath, name), nil, mods...)
+	return err
+}
+
+func (s *FabricService) save(method, name string, template Template, mods ...func(*nd.Req)) (Fabric, error) {
+	nvPairs, err := EncodeNvPairs(template)
+	if err != nil {
+		return Fabric{}, err
+	}
+	if _, ok := nvPairs["FABRIC_NAME"]; !ok {
+		nvPairs["FABRIC_NAME"] = name
+	}
+	res, err := s.client.Request(method, path(fabricsPath, name, template.TemplateName()), nvPairs, mods...)
+	if err != nil {
+		return Fabric{}, err
+	}
+	fabric := Fabric{Name: name, TemplateName: template.TemplateName(), NvPairs: nvPairs}
+	err = decodeRes(res, &fabric)
+	return fabric, err
+}
@@ -0,0 +1,115 @@
+package ndfc
+
+import (
+	"os"
+	"testing"
+
+	nd "github.com/netascode/go-nd"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const (
+	testURL      = "https://10.0.0.1"
+	testBasePath = "/appcenter/cisco/ndfc/api/v1"
+)
+
+func testClient() *nd.Client {
+	client, _ := nd.NewClient(testURL, testBasePath, "usr", "pwd", "", true, nd.BearerToken("token"), nd.MaxRetries(0))
+	gock.InterceptClient(client.HttpClient)
+	return &client
+}
+
+func testData(name string) string {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// TestFabricServiceList tests the FabricService::List method.
+func TestFabricServiceList(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+
+	gock.New(testURL).Get(testBasePath + "/lan-fabric/rest/control/fabrics").Reply(200).BodyString(testData("fabrics.json"))
+	list, err := fabrics.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "fabric1", list[0].Name)
+	assert.Equal(t, "External_Fabric", list[1].TemplateName)
+
+	var settings ExternalFabric
+	assert.NoError(t, list[1].Settings(&settings))
+	assert.Equal(t, Bool(true), settings.IsReadOnly)
+	assert.Error(t, list[0].Settings(&settings))
+}
+
+// TestFabricServiceGet tests the FabricService::Get method.
+func TestFabricServiceGet(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+
+	gock.New(testURL).Get(testBasePath + "/lan-fabric/rest/control/fabrics/fabric1").Reply(200).BodyString(testData("fabric.json"))
+	fabric, err := fabrics.Get("fabric1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), fabric.ID)
+
+	var settings EasyFabric
+	assert.NoError(t, fabric.Settings(&settings))
+	assert.Equal(t, "65001", settings.BgpAs)
+	assert.Equal(t, 9216, settings.FabricMtu)
+	assert.Equal(t, Bool(false), settings.EnableTrm)
+	assert.Equal(t, "", settings.Extra["BANNER"])
+	assert.Equal(t, "false", settings.Extra["BFD_ENABLE"])
+
+	// Round trip
+	nvPairs, err := EncodeNvPairs(&settings)
+	assert.NoError(t, err)
+	assert.Equal(t, fabric.NvPairs, nvPairs)
+
+	gock.New(testURL).Get(testBasePath + "/lan-fabric/rest/control/fabrics/missing").Reply(404)
+	_, err = fabrics.Get("missing")
+	assert.True(t, nd.IsNotFound(err))
+}
+
+// TestFabricServiceCreate tests the FabricService::Create method.
+func TestFabricServiceCreate(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+
+	gock.New(testURL).Post(testBasePath + "/lan-fabric/rest/control/fabrics/fabric1/Easy_Fabric").
+		BodyString(`{"BGP_AS":"65001","ENABLE_NXAPI":"true","FABRIC_NAME":"fabric1"}`).
+		Reply(200).BodyString(testData("fabric.json"))
+	fabric, err := fabrics.Create("fabric1", &EasyFabric{BgpAs: "65001", EnableNxapi: Bool(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, "FABRIC-3", fabric.FabricID)
+
+	gock.New(testURL).Post(testBasePath + "/lan-fabric/rest/control/fabrics/msd1/MSD_Fabric").Reply(200)
+	fabric, err = fabrics.Create("msd1", MSDFabric{})
+	assert.NoError(t, err)
+	assert.Equal(t, "MSD_Fabric", fabric.TemplateName)
+	assert.Equal(t, map[string]string{"FABRIC_NAME": "msd1"}, fabric.NvPairs)
+}
+
+// TestFabricServiceUpdate tests the FabricService::Update method.
+func TestFabricServiceUpdate(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+
+	gock.New(testURL).Put(testBasePath + "/lan-fabric/rest/control/fabrics/external1/External_Fabric").
+		BodyString(`{"BGP_AS":"65100","FABRIC_NAME":"external1","IS_READ_ONLY":"false"}`).
+		Reply(400).BodyString(`{"message": "Invalid BGP AS"}`)
+	_, err := fabrics.Update("external1", &ExternalFabric{FabricName: "external1", BgpAs: "65100", IsReadOnly: Bool(false)})
+	assert.ErrorContains(t, err, "Invalid BGP AS")
+}
+
+// TestFabricServiceDelete tests the FabricService::Delete method.
+func TestFabricServiceDelete(t *testing.T) {
+	defer gock.Off()
+	fabrics := NewFabricService(testClient())
+
+	gock.New(testURL).Delete(testBasePath + "/lan-fabric/rest/control/fabrics/fabric1").Reply(200)
+	assert.NoError(t, fabrics.Delete("fabric1"))
+}
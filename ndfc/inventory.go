@@ -0,0 +1,268 @@
+package ndfc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// DefaultPollInterval is the default interval between two status requests of long running workflows.
+const DefaultPollInterval = 10 * time.Second
+
+// Switch is a switch of the NDFC inventory.
+type Switch struct {
+	SerialNumber string `json:"serialNumber"`
+	Name         string `json:"logicalName"`
+	IPAddress    string `json:"ipAddress"`
+	Role         string `json:"switchRole"`
+	Model        string `json:"model"`
+	Release      string `json:"release"`
+	FabricName   string `json:"fabricName"`
+	Status       string `json:"status"`
+	Mode         string `json:"mode"`
+	CCStatus     string `json:"ccStatus"`
+	Managable    bool   `json:"managable"`
+}
+
+// Discovery holds the parameters to discover switches.
+type Discovery struct {
+	// SeedIPs are the management IP addresses of the switches.
+	SeedIPs []string
+	// Username and Password are the switch credentials.
+	Username string
+	Password string
+	// AuthProtocol is the SNMPv3 authentication protocol, 0 for MD5.
+	AuthProtocol int
+	// MaxHops is the number of hops to discover neighbors of the seed switches.
+	MaxHops int
+	// PreserveConfig keeps the existing switch configuration, required for brownfield imports.
+	PreserveConfig bool
+	// Timeout is the CDP timeout, rounded up to full seconds, 5 seconds if zero.
+	Timeout time.Duration
+}
+
+// ReachableSwitch is the result of a reachability test.
+type ReachableSwitch struct {
+	IPAddress    string  `json:"ipaddr"`
+	SysName      string  `json:"sysName"`
+	SerialNumber string  `json:"serialNumber"`
+	DeviceIndex  string  `json:"deviceIndex"`
+	Platform     string  `json:"platform"`
+	Version      string  `json:"version"`
+	VdcID        int     `json:"vdcId"`
+	VdcMac       *string `json:"vdcMac"`
+	Reachable    bool    `json:"reachable"`
+	Auth         bool    `json:"auth"`
+	Known        bool    `json:"known"`
+	Valid        bool    `json:"valid"`
+	Selectable   bool    `json:"selectable"`
+	StatusReason string  `json:"statusReason"`
+}
+
+// SwitchResult is the outcome of a workflow step for a single switch.
+type SwitchResult struct {
+	SerialNumber string
+	IPAddress    string
+	Name         string
+	// Status is the last observed status, e.g. "discovered" or the inventory status like "ok".
+	Status string
+	// Err is the reason the switch failed, nil on success.
+	Err error
+}
+
+// InventoryService manages the switches of NDFC fabrics.
+type InventoryService struct {
+	client *nd.Client
+	// PollInterval is the interval between two status requests, DefaultPollInterval if zero.
+	PollInterval time.Duration
+}
+
+// NewInventoryService creates a new InventoryService using the client.
+func NewInventoryService(client *nd.Client) *InventoryService {
+	return &InventoryService{client: client}
+}
+
+// Switches returns all switches of a fabric.
+func (s *InventoryService) Switches(fabric string, mods ...func(*nd.Req)) ([]Switch, error) {
+	return nd.GetAs[[]Switch](s.client, path(fabricsPath, fabric)+"/inventory/switchesByFabric", mods...)
+}
+
+// TestReachability tests whether the switches can be reached and logged in to with the credentials.
+func (s *InventoryService) TestReachability(ctx context.Context, fabric string, discovery Discovery) ([]ReachableSwitch, error) {
+	res, err := s.client.Post(path(fabricsPath, fabric)+"/inventory/test-reachability", discovery.body(), nd.Context(ctx), nd.Idempotent)
+	if err != nil {
+		return nil, err
+	}
+	var switches []ReachableSwitch
+	err = decodeRes(res, &switches)
+	return switches, err
+}
+
+// DiscoverSwitches tests the reachability of the switches and adds all reachable switches to the fabric.
+// Switches already known to NDFC are skipped. The progress function, if not nil, is called for every switch.
+// Failed switches are reported in the results and additionally returned as a joined error.
+func (s *InventoryService) DiscoverSwitches(ctx context.Context, fabric string, discovery Discovery, progress func(SwitchResult)) ([]SwitchResult, error) {
+	reachable, err := s.TestReachability(ctx, fabric, discovery)
+	if err != nil {
+		return nil, err
+	}
+	var results []SwitchResult
+	var selected []ReachableSwitch
+	report := func(result SwitchResult) {
+		results = append(results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+	for _, sw := range reachable {
+		result := SwitchResult{SerialNumber: sw.SerialNumber, IPAddress: sw.IPAddress, Name: sw.SysName}
+		switch {
+		case sw.Known:
+			result.Status = "known"
+		case !sw.Reachable:
+			result.Status = "unreachable"
+			result.Err = fmt.Errorf("switch %s not reachable: %s", sw.IPAddress, sw.StatusReason)
+		case !sw.Auth:
+			result.Status = "unauthorized"
+			result.Err = fmt.Errorf("switch %s authentication failed: %s", sw.IPAddress, sw.StatusReason)
+		case !sw.Valid || !sw.Selectable:
+			result.Status = "invalid"
+			result.Err = fmt.Errorf("switch %s cannot be discovered: %s", sw.IPAddress, sw.StatusReason)
+		default:
+			selected = append(selected, sw)
+			continue
+		}
+		report(result)
+	}
+	if len(selected) > 0 {
+		raw, err := json.Marshal(selected)
+		if err != nil {
+			return results, err
+		}
+		_, err = s.client.Post(path(fabricsPath, fabric)+"/inventory/discover", discovery.body().SetRaw("switches", string(raw)), nd.Context(ctx))
+		for _, sw := range selected {
+			result := SwitchResult{SerialNumber: sw.SerialNumber, IPAddress: sw.IPAddress, Name: sw.SysName, Status: "discovered"}
+			if err != nil {
+				result.Status = "failed"
+				result.Err = fmt.Errorf("switch %s discovery failed: %w", sw.IPAddress, err)
+			}
+			report(result)
+		}
+	}
+	return results, resultsErr(results)
+}
+
+// SetRoles sets the roles of switches by serial number, e.g. leaf, spine or border_gateway.
+func (s *InventoryService) SetRoles(ctx context.Context, roles map[string]string) error {
+	body := nd.Body{Str: "[]"}
+	for _, serial := range slices.Sorted(maps.Keys(roles)) {
+		body = body.Append("", map[string]string{"serialNumber": serial, "role": roles[serial]})
+	}
+	_, err := s.client.Post("/lan-fabric/rest/control/switches/roles", body, nd.Context(ctx), nd.Idempotent)
+	return err
+}
+
+// RemoveSwitch removes a switch from a fabric.
+func (s *InventoryService) RemoveSwitch(ctx context.Context, fabric, serial string) error {
+	_, err := s.client.Delete(path(fabricsPath, fabric, "switches", serial), nil, nd.Context(ctx))
+	return err
+}
+
+// WaitForSwitchState polls the inventory until all switches have reached the status, e.g. "ok".
+// The progress function, if not nil, is called whenever the status of a switch changes.
+// If ctx is done before, a *nd.WaitTimeoutError is returned and the switches which have not reached the status are reported with an error.
+func (s *InventoryService) WaitForSwitchState(ctx context.Context, fabric string, serials []string, status string, progress func(SwitchResult)) ([]SwitchResult, error) {
+	results := make([]SwitchResult, len(serials))
+	for i, serial := range serials {
+		results[i] = SwitchResult{SerialNumber: serial}
+	}
+	done := func() bool {
+		for _, result := range results {
+			if !strings.EqualFold(result.Status, status) {
+				return false
+			}
+		}
+		return true
+	}
+	err := waitForSwitches(ctx, s.client, fabric, s.PollInterval, func(switches []Switch) bool {
+		for i := range results {
+			current := "missing"
+			for _, sw := range switches {
+				if sw.SerialNumber == results[i].SerialNumber {
+					current = sw.Status
+					results[i].IPAddress = sw.IPAddress
+					results[i].Name = sw.Name
+				}
+			}
+			if current != results[i].Status {
+				results[i].Status = current
+				if progress != nil {
+					progress(results[i])
+				}
+			}
+		}
+		return done()
+	})
+	if err != nil {
+		for i := range results {
+			if !strings.EqualFold(results[i].Status, status) {
+				results[i].Err = fmt.Errorf("switch %s did not reach status %s: %w", results[i].SerialNumber, status, err)
+			}
+		}
+		return results, err
+	}
+	return results, nil
+}
+
+func (d Discovery) body() nd.Body {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return nd.Body{}.
+		Set("seedIP", strings.Join(d.SeedIPs, ",")).
+		Set("username", d.Username).
+		Set("password", d.Password).
+		SetInt("snmpV3AuthProtocol", int64(d.AuthProtocol)).
+		SetInt("maxHops", int64(d.MaxHops)).
+		SetInt("cdpSecondTimeout", int64((timeout+time.Second-1)/time.Second)).
+		SetBool("preserveConfig", d.PreserveConfig)
+}
+
+// resultsErr joins the errors of all failed switches.
+func resultsErr(results []SwitchResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitForSwitches polls the switches of a fabric every interval until fn returns true.
+// Polling stops with an error if the switches cannot be decoded.
+func waitForSwitches(ctx context.Context, client *nd.Client, fabric string, interval time.Duration, fn func([]Switch) bool) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	var decodeErr error
+	_, err := client.WaitFor(ctx, path(fabricsPath, fabric)+"/inventory/switchesByFabric", nd.WaitCondition{
+		Success: func(res nd.Res) bool {
+			var switches []Switch
+			decodeErr = decodeRes(res, &switches)
+			return decodeErr != nil || fn(switches)
+		},
+	}, nd.WaitInterval(interval))
+	if decodeErr != nil {
+		return decodeErr
+	}
+	return err
+}
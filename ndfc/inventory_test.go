@@ -0,0 +1,115 @@
+package ndfc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const inventoryPath = testBasePath + "/lan-fabric/rest/control/fabrics/fabric1/inventory"
+
+// TestInventoryServiceDiscoverSwitches tests the InventoryService::DiscoverSwitches method.
+func TestInventoryServiceDiscoverSwitches(t *testing.T) {
+	defer gock.Off()
+	inventory := NewInventoryService(testClient())
+	discovery := Discovery{SeedIPs: []string{"10.0.0.11", "10.0.0.12", "10.0.0.13"}, Username: "admin", Password: "secret", PreserveConfig: true}
+
+	gock.New(testURL).Post(inventoryPath + "/test-reachability").
+		BodyString(`{"seedIP":"10.0.0.11,10.0.0.12,10.0.0.13","username":"admin","password":"secret","snmpV3AuthProtocol":0,"maxHops":0,"cdpSecondTimeout":5,"preserveConfig":true}`).
+		Reply(200).BodyString(testData("test-reachability.json"))
+	gock.New(testURL).Post(inventoryPath + "/discover").
+		AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+			body, _ := io.ReadAll(req.Body)
+			res := gjson.ParseBytes(body)
+			return res.Get("switches.#").Int() == 1 && res.Get("switches.0.serialNumber").String() == "9A1B2C3D4E5" &&
+				res.Get("preserveConfig").Bool(), nil
+		}).
+		Reply(200)
+
+	var progress []string
+	results, err := inventory.DiscoverSwitches(context.Background(), "fabric1", discovery, func(result SwitchResult) {
+		progress = append(progress, result.IPAddress+" "+result.Status)
+	})
+	assert.ErrorContains(t, err, "switch 10.0.0.13 not reachable: Unable to reach switch")
+	assert.Len(t, results, 3)
+	assert.Equal(t, []string{"10.0.0.12 known", "10.0.0.13 unreachable", "10.0.0.11 discovered"}, progress)
+	assert.NoError(t, results[2].Err)
+	assert.True(t, gock.IsDone())
+
+	// Discovery failure
+	gock.New(testURL).Post(inventoryPath + "/test-reachability").Reply(200).BodyString(testData("test-reachability.json"))
+	gock.New(testURL).Post(inventoryPath + "/discover").Reply(500).BodyString(`{"message": "Discovery failed"}`)
+	results, err = inventory.DiscoverSwitches(context.Background(), "fabric1", discovery, nil)
+	assert.ErrorContains(t, err, "switch 10.0.0.11 discovery failed")
+	assert.Equal(t, "failed", results[2].Status)
+}
+
+// TestInventoryServiceSetRoles tests the InventoryService::SetRoles method.
+func TestInventoryServiceSetRoles(t *testing.T) {
+	defer gock.Off()
+	inventory := NewInventoryService(testClient())
+
+	gock.New(testURL).Post(testBasePath + "/lan-fabric/rest/control/switches/roles").
+		BodyString(`[{"role":"spine","serialNumber":"A"},{"role":"leaf","serialNumber":"B"}]`).
+		Reply(200)
+	err := inventory.SetRoles(context.Background(), map[string]string{"B": "leaf", "A": "spine"})
+	assert.NoError(t, err)
+}
+
+// TestInventoryServiceRemoveSwitch tests the InventoryService::RemoveSwitch method.
+func TestInventoryServiceRemoveSwitch(t *testing.T) {
+	defer gock.Off()
+	inventory := NewInventoryService(testClient())
+
+	gock.New(testURL).Delete(testBasePath + "/lan-fabric/rest/control/fabrics/fabric1/switches/9A1B2C3D4E5").Reply(200)
+	assert.NoError(t, inventory.RemoveSwitch(context.Background(), "fabric1", "9A1B2C3D4E5"))
+}
+
+// TestInventoryServiceWaitForSwitchState tests the InventoryService::WaitForSwitchState method.
+func TestInventoryServiceWaitForSwitchState(t *testing.T) {
+	defer gock.Off()
+	inventory := NewInventoryService(testClient())
+	inventory.PollInterval = time.Millisecond
+
+	gock.New(testURL).Get(inventoryPath + "/switchesByFabric").Reply(200).BodyString(testData("switches-discovering.json"))
+	gock.New(testURL).Get(inventoryPath + "/switchesByFabric").Reply(200).BodyString(testData("switches.json"))
+	var progress []string
+	results, err := inventory.WaitForSwitchState(context.Background(), "fabric1", []string{"9A1B2C3D4E5"}, "ok", func(result SwitchResult) {
+		progress = append(progress, result.Status)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"discovering", "ok"}, progress)
+	assert.Equal(t, "leaf1", results[0].Name)
+
+	// Timeout
+	gock.New(testURL).Get(inventoryPath + "/switchesByFabric").Persist().Reply(200).BodyString(testData("switches-discovering.json"))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results, err = inventory.WaitForSwitchState(ctx, "fabric1", []string{"9A1B2C3D4E5", "missing"}, "ok", nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "missing", results[1].Status)
+	assert.Error(t, results[0].Err)
+	gock.Off()
+
+	// Unexpected response stops polling
+	gock.New(testURL).Get(inventoryPath + "/switchesByFabric").Reply(200).BodyString(`{"switches": []}`)
+	_, err = inventory.WaitForSwitchState(context.Background(), "fabric1", []string{"9A1B2C3D4E5"}, "ok", nil)
+	var decodeErr *nd.DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.True(t, gock.IsDone())
+}
+
+// TestDiscoveryTimeout tests the conversion of the Discovery timeout to seconds.
+func TestDiscoveryTimeout(t *testing.T) {
+	assert.Equal(t, int64(5), Discovery{}.body().Res().Get("cdpSecondTimeout").Int())
+	assert.Equal(t, int64(10), Discovery{Timeout: 10 * time.Second}.body().Res().Get("cdpSecondTimeout").Int())
+	assert.Equal(t, int64(2), Discovery{Timeout: 1500 * time.Millisecond}.body().Res().Get("cdpSecondTimeout").Int())
+}
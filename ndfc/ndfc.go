@@ -0,0 +1,40 @@
+// Package ndfc provides services for the Nexus Dashboard Fabric Controller (NDFC) API on top of an nd.Client.
+//
+// The client must be created with the NDFC base path, e.g.
+//
+//	client, _ := nd.NewClient("https://10.0.0.1", "/appcenter/cisco/ndfc/api/v1", "user", "password", "", true)
+//	fabrics := ndfc.NewFabricService(&client)
+//	list, _ := fabrics.List()
+package ndfc
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// Bool returns a pointer to v, e.g. to set optional template settings.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// decodeRes decodes a GJSON result into v. An empty result leaves v unchanged.
+func decodeRes(res nd.Res, v any) error {
+	if strings.TrimSpace(res.Raw) == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(res.Raw), v); err != nil {
+		return &nd.DecodeError{Err: err}
+	}
+	return nil
+}
+
+// path joins path segments, escaping all but the first one.
+func path(base string, segments ...string) string {
+	for _, segment := range segments {
+		base += "/" + url.PathEscape(segment)
+	}
+	return base
+}
@@ -0,0 +1,146 @@
+package ndfc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// Network is an NDFC overlay network.
+type Network struct {
+	Fabric            string         `json:"fabric"`
+	Name              string         `json:"networkName"`
+	ID                int64          `json:"networkId,omitempty"`
+	VRF               string         `json:"vrf"`
+	Template          string         `json:"networkTemplate"`
+	ExtensionTemplate string         `json:"networkExtensionTemplate"`
+	TemplateConfig    TemplateConfig `json:"networkTemplateConfig"`
+	Status            string         `json:"networkStatus,omitempty"`
+}
+
+// NetworkAttachment attaches a network to the ports of a switch.
+type NetworkAttachment struct {
+	NetworkName     string   `json:"-"`
+	SerialNumber    string   `json:"serialNumber"`
+	SwitchPorts     []string `json:"-"`
+	Vlan            int      `json:"vlan,omitempty"`
+	Dot1QVlan       int      `json:"dot1QVlan,omitempty"`
+	Untagged        bool     `json:"untagged"`
+	FreeformConfig  string   `json:"freeformConfig"`
+	ExtensionValues string   `json:"extensionValues"`
+	InstanceValues  string   `json:"instanceValues"`
+}
+
+// MarshalJSON encodes the switch ports as comma separated list.
+func (a NetworkAttachment) MarshalJSON() ([]byte, error) {
+	type attachment NetworkAttachment
+	return json.Marshal(struct {
+		attachment
+		SwitchPorts string `json:"switchPorts"`
+	}{attachment(a), strings.Join(a.SwitchPorts, ",")})
+}
+
+// NetworkService manages the overlay networks of NDFC fabrics.
+type NetworkService struct {
+	overlay overlay
+	// PollInterval is the interval between two status requests while deploying, DefaultPollInterval if zero.
+	PollInterval time.Duration
+	// Timeout is the maximum duration of a deployment, DefaultDeployTimeout if zero.
+	Timeout time.Duration
+}
+
+// NewNetworkService creates a new NetworkService using the client.
+func NewNetworkService(client *nd.Client) *NetworkService {
+	return &NetworkService{overlay: overlay{client: client, kind: "network"}}
+}
+
+// List returns all networks of a fabric.
+func (s *NetworkService) List(fabric string, mods ...func(*nd.Req)) ([]Network, error) {
+	return nd.GetAs[[]Network](s.overlay.client, s.overlay.path(fabric), mods...)
+}
+
+// Get returns a network by name. A missing network results in an error matched by nd.IsNotFound.
+func (s *NetworkService) Get(fabric, name string, mods ...func(*nd.Req)) (Network, error) {
+	return nd.GetAs[Network](s.overlay.client, s.overlay.path(fabric, name), mods...)
+}
+
+// Create creates a network, e.g.
+//
+//	network, err := networks.Create("fabric1", ndfc.Network{Name: "NET1", ID: 30001, VRF: "VRF1",
+//		TemplateConfig: ndfc.TemplateConfig{"vlanId": "2301", "gatewayIpAddress": "10.1.1.1/24"}})
+//
+// The default templates are used if none are set.
+// The networkName, segmentId and vrfName template settings default to Name, ID and VRF.
+func (s *NetworkService) Create(fabric string, network Network, mods ...func(*nd.Req)) (Network, error) {
+	res, err := s.overlay.client.Post(s.overlay.path(fabric), network.prepare(fabric), mods...)
+	if err != nil {
+		return Network{}, err
+	}
+	err = decodeRes(res, &network)
+	return network, err
+}
+
+// Update updates a network.
+func (s *NetworkService) Update(fabric string, network Network, mods ...func(*nd.Req)) (Network, error) {
+	res, err := s.overlay.client.Put(s.overlay.path(fabric, network.Name), network.prepare(fabric), mods...)
+	if err != nil {
+		return Network{}, err
+	}
+	err = decodeRes(res, &network)
+	return network, err
+}
+
+// Delete deletes a network, which must not be attached to any switch.
+func (s *NetworkService) Delete(fabric, name string, mods ...func(*nd.Req)) error {
+	_, err := s.overlay.client.Delete(s.overlay.path(fabric, name), nil, mods...)
+	return err
+}
+
+// Attach attaches networks to switch ports in a single request. The attachments are deployed with Deploy.
+func (s *NetworkService) Attach(ctx context.Context, fabric string, attachments ...NetworkAttachment) error {
+	return s.overlay.attach(ctx, fabric, true, toAny(attachments), func(i int) string { return attachments[i].NetworkName })
+}
+
+// Detach detaches networks from switches in a single request. The detachments are deployed with Deploy.
+func (s *NetworkService) Detach(ctx context.Context, fabric string, attachments ...NetworkAttachment) error {
+	return s.overlay.attach(ctx, fabric, false, toAny(attachments), func(i int) string { return attachments[i].NetworkName })
+}
+
+// Attachments returns the attachment status of networks on all switches.
+func (s *NetworkService) Attachments(fabric string, names []string, mods ...func(*nd.Req)) ([]AttachmentStatus, error) {
+	return s.overlay.attachments(fabric, names, mods...)
+}
+
+// Deploy deploys networks and waits until all of them are deployed, one of them failed or is missing,
+// or the Timeout of the service has elapsed or ctx is done.
+// The last observed status of the networks is returned in any case.
+func (s *NetworkService) Deploy(ctx context.Context, fabric string, names ...string) ([]DeploymentStatus, error) {
+	return s.overlay.deploy(ctx, fabric, names, s.PollInterval, s.Timeout)
+}
+
+func (network Network) prepare(fabric string) Network {
+	network.Fabric = fabric
+	if network.Template == "" {
+		network.Template = "Default_Network_Universal"
+	}
+	if network.ExtensionTemplate == "" {
+		network.ExtensionTemplate = "Default_Network_Extension_Universal"
+	}
+	config := TemplateConfig{"networkName": network.Name}
+	if network.ID != 0 {
+		config["segmentId"] = strconv.FormatInt(network.ID, 10)
+	}
+	if network.VRF != "" {
+		config["vrfName"] = network.VRF
+	}
+	for k, v := range network.TemplateConfig {
+		config[k] = v
+	}
+	network.TemplateConfig = config
+	network.Status = ""
+	return network
+}
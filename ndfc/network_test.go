@@ -0,0 +1,100 @@
+package ndfc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const networksPath = testBasePath + "/top-down/fabrics/fabric1/networks"
+
+// TestNetworkServiceGet tests the NetworkService::List and NetworkService::Get methods.
+func TestNetworkServiceGet(t *testing.T) {
+	defer gock.Off()
+	networks := NewNetworkService(testClient())
+
+	gock.New(testURL).Get(networksPath).Reply(200).BodyString(testData("networks.json"))
+	list, err := networks.List("fabric1")
+	assert.NoError(t, err)
+	assert.Equal(t, "VRF1", list[0].VRF)
+	assert.Equal(t, "10.1.1.1/24", list[0].TemplateConfig["gatewayIpAddress"])
+
+	gock.New(testURL).Get(networksPath + "/NET1").Reply(200).BodyString(`{"networkName": "NET1", "networkId": 30001, "networkTemplateConfig": "{}"}`)
+	network, err := networks.Get("fabric1", "NET1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30001), network.ID)
+}
+
+// TestNetworkServiceCreate tests the NetworkService::Create, NetworkService::Update and NetworkService::Delete methods.
+func TestNetworkServiceCreate(t *testing.T) {
+	defer gock.Off()
+	networks := NewNetworkService(testClient())
+
+	gock.New(testURL).Post(networksPath).
+		BodyString(`{"fabric":"fabric1","networkName":"NET1","networkId":30001,"vrf":"VRF1","networkTemplate":"Default_Network_Universal",` +
+			`"networkExtensionTemplate":"Default_Network_Extension_Universal",` +
+			`"networkTemplateConfig":"{\"networkName\":\"NET1\",\"segmentId\":\"30001\",\"vlanId\":\"2301\",\"vrfName\":\"VRF1\"}"}`).
+		Reply(200).BodyString(`{"networkName": "NET1", "networkStatus": "NA"}`)
+	network, err := networks.Create("fabric1", Network{Name: "NET1", ID: 30001, VRF: "VRF1", TemplateConfig: TemplateConfig{"vlanId": "2301"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "NET1", network.Name)
+	assert.Equal(t, "NA", network.Status)
+
+	gock.New(testURL).Put(networksPath + "/NET1").Reply(200).BodyString(`{"networkName": "NET1", "networkStatus": "PENDING"}`)
+	network, err = networks.Update("fabric1", Network{Name: "NET1", Template: "Custom_Network"})
+	assert.NoError(t, err)
+	assert.Equal(t, "PENDING", network.Status)
+
+	gock.New(testURL).Delete(networksPath + "/NET1").Reply(200)
+	assert.NoError(t, networks.Delete("fabric1", "NET1"))
+}
+
+// TestNetworkServiceAttach tests the NetworkService::Attach and NetworkService::Detach methods.
+func TestNetworkServiceAttach(t *testing.T) {
+	defer gock.Off()
+	networks := NewNetworkService(testClient())
+
+	gock.New(testURL).Post(networksPath + "/attachments").
+		BodyString(`[{"networkName":"NET1","lanAttachList":[` +
+			`{"serialNumber":"A","vlan":2301,"untagged":false,"freeformConfig":"","extensionValues":"","instanceValues":"",` +
+			`"switchPorts":"Ethernet1/1,Ethernet1/2","fabric":"fabric1","networkName":"NET1","deployment":true}]}]`).
+		Reply(200)
+	err := networks.Attach(context.Background(), "fabric1",
+		NetworkAttachment{NetworkName: "NET1", SerialNumber: "A", Vlan: 2301, SwitchPorts: []string{"Ethernet1/1", "Ethernet1/2"}})
+	assert.NoError(t, err)
+
+	gock.New(testURL).Post(networksPath + "/attachments").
+		AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+			body, _ := io.ReadAll(req.Body)
+			return gjson.GetBytes(body, "0.lanAttachList.0.deployment").Type == gjson.False, nil
+		}).
+		Reply(200)
+	err = networks.Detach(context.Background(), "fabric1", NetworkAttachment{NetworkName: "NET1", SerialNumber: "A"})
+	assert.NoError(t, err)
+
+	gock.New(testURL).Get(networksPath+"/attachments").MatchParam("network-names", "NET1,NET2").Reply(200).
+		BodyString(`[{"networkName": "NET1", "lanAttachList": [{"switchSerialNo": "A", "lanAttachState": "PENDING", "portNames": "Ethernet1/1"}]}]`)
+	attachments, err := networks.Attachments("fabric1", []string{"NET1", "NET2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ethernet1/1", attachments[0].Ports)
+	assert.Equal(t, "PENDING", attachments[0].State)
+}
+
+// TestNetworkServiceDeploy tests the NetworkService::Deploy method.
+func TestNetworkServiceDeploy(t *testing.T) {
+	defer gock.Off()
+	networks := NewNetworkService(testClient())
+	networks.PollInterval = time.Millisecond
+
+	gock.New(testURL).Post(networksPath + "/deployments").BodyString(`{"networkNames":"NET1"}`).Reply(200)
+	gock.New(testURL).Get(networksPath).Reply(200).BodyString(testData("networks.json"))
+	statuses, err := networks.Deploy(context.Background(), "fabric1", "NET1")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeploymentStatus{{Name: "NET1", Status: "DEPLOYED"}}, statuses)
+}
@@ -0,0 +1,190 @@
+package ndfc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// TemplateConfig is the template configuration of a VRF or network.
+// NDFC expects it as a JSON encoded string nested in the JSON payload, which is handled transparently.
+type TemplateConfig map[string]any
+
+// MarshalJSON encodes the template configuration as a JSON string.
+func (c TemplateConfig) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte(`"{}"`), nil
+	}
+	raw, err := json.Marshal(map[string]any(c))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(raw))
+}
+
+// UnmarshalJSON decodes a template configuration encoded as a JSON string or object.
+func (c *TemplateConfig) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			return nil
+		}
+		data = []byte(s)
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]any)(c))
+}
+
+// DeploymentStatus is the deployment status of a VRF or network.
+type DeploymentStatus struct {
+	Name string
+	// Status is the last observed status, e.g. DEPLOYED, PENDING, OUT-OF-SYNC or FAILED.
+	Status string
+}
+
+// AttachmentStatus is the attachment status of a VRF or network on a switch.
+type AttachmentStatus struct {
+	Name         string
+	SerialNumber string
+	SwitchName   string
+	// State is the attachment state, e.g. DEPLOYED, PENDING or NA.
+	State    string
+	Attached bool
+	Vlan     int64
+	Ports    string
+}
+
+// overlay implements the API shared by VRFs and networks.
+type overlay struct {
+	client *nd.Client
+	// kind is either "vrf" or "network"
+	kind string
+}
+
+func (o overlay) path(fabric string, segments ...string) string {
+	return path("/top-down/fabrics", append([]string{fabric, o.kind + "s"}, segments...)...)
+}
+
+// attach attaches or detaches VRFs or networks in bulk. Each attachment is marshaled to a
+// lanAttachList entry of the VRF or network returned by name.
+func (o overlay) attach(ctx context.Context, fabric string, deployment bool, attachments []any, name func(int) string) error {
+	var names []string
+	lists := map[string]nd.Body{}
+	for i, attachment := range attachments {
+		raw, err := json.Marshal(attachment)
+		if err != nil {
+			return err
+		}
+		n := name(i)
+		entry := nd.Body{Str: string(raw)}.
+			Set("fabric", fabric).
+			Set(o.kind+"Name", n).
+			SetBool("deployment", deployment)
+		if _, ok := lists[n]; !ok {
+			names = append(names, n)
+			lists[n] = nd.Body{Str: "[]"}
+		}
+		lists[n] = lists[n].SetRaw("-1", entry.Str)
+		if err := lists[n].Err(); err != nil {
+			return err
+		}
+	}
+	body := nd.Body{Str: "[]"}
+	for _, n := range names {
+		body = body.SetRaw("-1", nd.Body{}.Set(o.kind+"Name", n).SetRaw("lanAttachList", lists[n].Str).Str)
+	}
+	_, err := o.client.Post(o.path(fabric, "attachments"), body, nd.Context(ctx))
+	return err
+}
+
+// attachments returns the attachment status of the VRFs or networks.
+func (o overlay) attachments(fabric string, names []string, mods ...func(*nd.Req)) ([]AttachmentStatus, error) {
+	res, err := o.client.Get(o.path(fabric, "attachments"), append(mods, nd.Query(o.kind+"-names", strings.Join(names, ",")))...)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []AttachmentStatus
+	for _, item := range res.Array() {
+		for _, attach := range item.Get("lanAttachList").Array() {
+			statuses = append(statuses, AttachmentStatus{
+				Name:         item.Get(o.kind + "Name").String(),
+				SerialNumber: attach.Get("switchSerialNo").String(),
+				SwitchName:   attach.Get("switchName").String(),
+				State:        attach.Get("lanAttachState").String(),
+				Attached:     attach.Get("isLanAttached").Bool(),
+				Vlan:         attach.Get("vlanId").Int(),
+				Ports:        attach.Get("portNames").String(),
+			})
+		}
+	}
+	return statuses, nil
+}
+
+// deploy deploys the VRFs or networks and waits until all of them are deployed, one of them failed or is missing,
+// or the timeout has elapsed.
+func (o overlay) deploy(ctx context.Context, fabric string, names []string, interval, timeout time.Duration) ([]DeploymentStatus, error) {
+	if timeout <= 0 {
+		timeout = DefaultDeployTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := o.client.Post(o.path(fabric, "deployments"), nd.Body{}.Set(o.kind+"Names", strings.Join(names, ",")), nd.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	// statuses returns the status of each name, empty if the name is missing
+	statuses := func(res nd.Res) []DeploymentStatus {
+		result := make([]DeploymentStatus, len(names))
+		for i, name := range names {
+			result[i] = DeploymentStatus{Name: name}
+			for _, item := range res.Array() {
+				if item.Get(o.kind+"Name").String() == name {
+					result[i].Status = item.Get(o.kind + "Status").String()
+				}
+			}
+		}
+		return result
+	}
+	res, err := o.client.WaitFor(ctx, o.path(fabric), nd.WaitCondition{
+		Success: func(res nd.Res) bool {
+			for _, s := range statuses(res) {
+				if !strings.EqualFold(s.Status, "DEPLOYED") {
+					return false
+				}
+			}
+			return true
+		},
+		Failure: func(res nd.Res) bool {
+			for _, s := range statuses(res) {
+				if s.Status == "" || strings.EqualFold(s.Status, "FAILED") {
+					return true
+				}
+			}
+			return false
+		},
+	}, nd.WaitInterval(interval))
+	var timeoutErr *nd.WaitTimeoutError
+	if errors.As(err, &timeoutErr) {
+		res = timeoutErr.Last
+	}
+	result := statuses(res)
+	var failedErr *nd.WaitFailedError
+	if errors.As(err, &failedErr) {
+		for _, s := range result {
+			if s.Status == "" {
+				return result, fmt.Errorf("%s %s not found in fabric %s: %w", o.kind, s.Name, fabric, err)
+			}
+		}
+	}
+	return result, err
+}
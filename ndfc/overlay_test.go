@@ -0,0 +1,32 @@
+package ndfc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTemplateConfig tests the encoding of TemplateConfig.
+func TestTemplateConfig(t *testing.T) {
+	raw, err := json.Marshal(struct {
+		Config TemplateConfig `json:"config"`
+		Empty  TemplateConfig `json:"empty"`
+	}{Config: TemplateConfig{"a": "1", "b": true}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"config":"{\"a\":\"1\",\"b\":true}","empty":"{}"}`, string(raw))
+
+	var config TemplateConfig
+	assert.NoError(t, json.Unmarshal([]byte(`"{\"a\":\"1\"}"`), &config))
+	assert.Equal(t, TemplateConfig{"a": "1"}, config)
+
+	config = nil
+	assert.NoError(t, json.Unmarshal([]byte(`{"a": "2"}`), &config))
+	assert.Equal(t, TemplateConfig{"a": "2"}, config)
+
+	config = nil
+	assert.NoError(t, json.Unmarshal([]byte(`""`), &config))
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &config))
+	assert.Nil(t, config)
+	assert.Error(t, json.Unmarshal([]byte(`"invalid"`), &config))
+}
@@ -0,0 +1,200 @@
+package ndfc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Template is a typed fabric template, whose settings are sent to NDFC as nvPairs.
+// Fields are mapped to nvPairs with the nv struct tag. Zero values and nil pointers are omitted,
+// so that NDFC applies the template defaults. The map field tagged with nv:",extra" passes
+// through all nvPairs without a typed field or with an empty value.
+type Template interface {
+	// TemplateName returns the name of the NDFC fabric template, e.g. Easy_Fabric.
+	TemplateName() string
+}
+
+// EasyFabric is the Easy_Fabric template for VXLAN EVPN fabrics.
+type EasyFabric struct {
+	FabricName           string            `nv:"FABRIC_NAME"`
+	BgpAs                string            `nv:"BGP_AS"`
+	SiteID               string            `nv:"SITE_ID"`
+	ReplicationMode      string            `nv:"REPLICATION_MODE"`
+	LinkStateRouting     string            `nv:"LINK_STATE_ROUTING"`
+	OverlayMode          string            `nv:"OVERLAY_MODE"`
+	FabricMtu            int               `nv:"FABRIC_MTU"`
+	L2HostIntfMtu        int               `nv:"L2_HOST_INTF_MTU"`
+	AnycastGwMac         string            `nv:"ANYCAST_GW_MAC"`
+	Loopback0IPRange     string            `nv:"LOOPBACK0_IP_RANGE"`
+	Loopback1IPRange     string            `nv:"LOOPBACK1_IP_RANGE"`
+	SubnetRange          string            `nv:"SUBNET_RANGE"`
+	MulticastGroupSubnet string            `nv:"MULTICAST_GROUP_SUBNET"`
+	L2SegmentIDRange     string            `nv:"L2_SEGMENT_ID_RANGE"`
+	L3PartitionIDRange   string            `nv:"L3_PARTITION_ID_RANGE"`
+	NetworkVlanRange     string            `nv:"NETWORK_VLAN_RANGE"`
+	VrfVlanRange         string            `nv:"VRF_VLAN_RANGE"`
+	VpcPeerLinkVlan      string            `nv:"VPC_PEER_LINK_VLAN"`
+	EnableTrm            *bool             `nv:"ENABLE_TRM"`
+	EnableNxapi          *bool             `nv:"ENABLE_NXAPI"`
+	AdvertisePipBgp      *bool             `nv:"ADVERTISE_PIP_BGP"`
+	EnableNetflow        *bool             `nv:"ENABLE_NETFLOW"`
+	Extra                map[string]string `nv:",extra"`
+}
+
+// TemplateName returns Easy_Fabric.
+func (EasyFabric) TemplateName() string {
+	return "Easy_Fabric"
+}
+
+// ExternalFabric is the External_Fabric template for external and classic LAN fabrics.
+type ExternalFabric struct {
+	FabricName  string            `nv:"FABRIC_NAME"`
+	BgpAs       string            `nv:"BGP_AS"`
+	IsReadOnly  *bool             `nv:"IS_READ_ONLY"`
+	InbandMgmt  *bool             `nv:"INBAND_MGMT"`
+	PmEnable    *bool             `nv:"PM_ENABLE"`
+	EnableNxapi *bool             `nv:"ENABLE_NXAPI"`
+	Extra       map[string]string `nv:",extra"`
+}
+
+// TemplateName returns External_Fabric.
+func (ExternalFabric) TemplateName() string {
+	return "External_Fabric"
+}
+
+// MSDFabric is the MSD_Fabric template for VXLAN EVPN multi-site domains.
+type MSDFabric struct {
+	FabricName           string            `nv:"FABRIC_NAME"`
+	AnycastGwMac         string            `nv:"ANYCAST_GW_MAC"`
+	L2SegmentIDRange     string            `nv:"L2_SEGMENT_ID_RANGE"`
+	L3PartitionIDRange   string            `nv:"L3_PARTITION_ID_RANGE"`
+	BorderGwyConnections string            `nv:"BORDER_GWY_CONNECTIONS"`
+	DciSubnetRange       string            `nv:"DCI_SUBNET_RANGE"`
+	DciSubnetTargetMask  int               `nv:"DCI_SUBNET_TARGET_MASK"`
+	Loopback100IPRange   string            `nv:"LOOPBACK100_IP_RANGE"`
+	MsIfcBgpPassword     string            `nv:"MS_IFC_BGP_PASSWORD"`
+	EnableRsRedistDirect *bool             `nv:"ENABLE_RS_REDIST_DIRECT"`
+	Extra                map[string]string `nv:",extra"`
+}
+
+// TemplateName returns MSD_Fabric.
+func (MSDFabric) TemplateName() string {
+	return "MSD_Fabric"
+}
+
+// EncodeNvPairs converts a struct with nv tags, e.g. a Template, to nvPairs.
+func EncodeNvPairs(v any) (map[string]string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("encoding nvPairs failed: %T is not a struct", v)
+	}
+	nvPairs := map[string]string{}
+	// Typed fields take precedence over the passthrough map
+	for i := 0; i < rv.NumField(); i++ {
+		if _, extra := nvTag(rv.Type().Field(i)); extra {
+			for k, v := range rv.Field(i).Interface().(map[string]string) {
+				nvPairs[k] = v
+			}
+		}
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		if key, extra := nvTag(rv.Type().Field(i)); key != "" && !extra {
+			encodeNv(nvPairs, key, rv.Field(i))
+		}
+	}
+	return nvPairs, nil
+}
+
+// encodeNv sets the nvPair for a field unless it has its zero value.
+func encodeNv(nvPairs map[string]string, key string, field reflect.Value) {
+	if field.IsZero() {
+		return
+	}
+	for field.Kind() == reflect.Pointer {
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.String:
+		nvPairs[key] = field.String()
+	case reflect.Bool:
+		nvPairs[key] = strconv.FormatBool(field.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		nvPairs[key] = strconv.FormatInt(field.Int(), 10)
+	default:
+		nvPairs[key] = fmt.Sprint(field.Interface())
+	}
+}
+
+// DecodeNvPairs fills a pointer to a struct with nv tags, e.g. a Template, from nvPairs.
+func DecodeNvPairs(nvPairs map[string]string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decoding nvPairs failed: %T is not a pointer to a struct", v)
+	}
+	rv = rv.Elem()
+	typed := map[string]bool{}
+	var extra reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		key, isExtra := nvTag(rv.Type().Field(i))
+		if isExtra {
+			extra = rv.Field(i)
+			continue
+		}
+		value, ok := nvPairs[key]
+		if key == "" || !ok || value == "" {
+			continue
+		}
+		if err := decodeNv(rv.Field(i), value); err != nil {
+			return fmt.Errorf("decoding nvPair %s failed: %w", key, err)
+		}
+		typed[key] = true
+	}
+	if !extra.IsValid() {
+		return nil
+	}
+	extraPairs := map[string]string{}
+	for k, v := range nvPairs {
+		if !typed[k] {
+			extraPairs[k] = v
+		}
+	}
+	extra.Set(reflect.ValueOf(extraPairs))
+	return nil
+}
+
+func decodeNv(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// nvTag returns the nvPair key of a field and whether it is the passthrough map.
+func nvTag(field reflect.StructField) (string, bool) {
+	key, opts, _ := strings.Cut(field.Tag.Get("nv"), ",")
+	return key, opts == "extra" && field.Type == reflect.TypeFor[map[string]string]()
+}
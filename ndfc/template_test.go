@@ -0,0 +1,51 @@
+package ndfc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncodeNvPairs tests the EncodeNvPairs function.
+func TestEncodeNvPairs(t *testing.T) {
+	nvPairs, err := EncodeNvPairs(&EasyFabric{
+		BgpAs:       "65001",
+		FabricMtu:   9216,
+		EnableNxapi: Bool(false),
+		Extra:       map[string]string{"BGP_AS": "1", "BFD_ENABLE": "true"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BGP_AS":       "65001",
+		"FABRIC_MTU":   "9216",
+		"ENABLE_NXAPI": "false",
+		"BFD_ENABLE":   "true",
+	}, nvPairs)
+
+	_, err = EncodeNvPairs("a")
+	assert.Error(t, err)
+}
+
+// TestDecodeNvPairs tests the DecodeNvPairs function.
+func TestDecodeNvPairs(t *testing.T) {
+	var settings EasyFabric
+	err := DecodeNvPairs(map[string]string{
+		"BGP_AS":       "65001",
+		"FABRIC_MTU":   "9216",
+		"ENABLE_NXAPI": "true",
+		"SITE_ID":      "",
+		"BFD_ENABLE":   "false",
+	}, &settings)
+	assert.NoError(t, err)
+	assert.Equal(t, "65001", settings.BgpAs)
+	assert.Equal(t, 9216, settings.FabricMtu)
+	assert.Equal(t, Bool(true), settings.EnableNxapi)
+	assert.Nil(t, settings.EnableTrm)
+	assert.Equal(t, map[string]string{"SITE_ID": "", "BFD_ENABLE": "false"}, settings.Extra)
+
+	err = DecodeNvPairs(map[string]string{"FABRIC_MTU": "jumbo"}, &settings)
+	assert.ErrorContains(t, err, "FABRIC_MTU")
+
+	err = DecodeNvPairs(map[string]string{}, settings)
+	assert.Error(t, err)
+}
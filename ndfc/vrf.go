@@ -0,0 +1,134 @@
+package ndfc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+)
+
+// VRF is an NDFC overlay VRF.
+type VRF struct {
+	Fabric            string         `json:"fabric"`
+	Name              string         `json:"vrfName"`
+	ID                int64          `json:"vrfId,omitempty"`
+	Template          string         `json:"vrfTemplate"`
+	ExtensionTemplate string         `json:"vrfExtensionTemplate"`
+	TemplateConfig    TemplateConfig `json:"vrfTemplateConfig"`
+	Status            string         `json:"vrfStatus,omitempty"`
+}
+
+// VRFAttachment attaches a VRF to a switch.
+type VRFAttachment struct {
+	VRFName         string `json:"-"`
+	SerialNumber    string `json:"serialNumber"`
+	Vlan            int    `json:"vlan,omitempty"`
+	FreeformConfig  string `json:"freeformConfig"`
+	ExtensionValues string `json:"extensionValues"`
+	InstanceValues  string `json:"instanceValues"`
+}
+
+// VRFService manages the overlay VRFs of NDFC fabrics.
+type VRFService struct {
+	overlay overlay
+	// PollInterval is the interval between two status requests while deploying, DefaultPollInterval if zero.
+	PollInterval time.Duration
+	// Timeout is the maximum duration of a deployment, DefaultDeployTimeout if zero.
+	Timeout time.Duration
+}
+
+// NewVRFService creates a new VRFService using the client.
+func NewVRFService(client *nd.Client) *VRFService {
+	return &VRFService{overlay: overlay{client: client, kind: "vrf"}}
+}
+
+// List returns all VRFs of a fabric.
+func (s *VRFService) List(fabric string, mods ...func(*nd.Req)) ([]VRF, error) {
+	return nd.GetAs[[]VRF](s.overlay.client, s.overlay.path(fabric), mods...)
+}
+
+// Get returns a VRF by name. A missing VRF results in an error matched by nd.IsNotFound.
+func (s *VRFService) Get(fabric, name string, mods ...func(*nd.Req)) (VRF, error) {
+	return nd.GetAs[VRF](s.overlay.client, s.overlay.path(fabric, name), mods...)
+}
+
+// Create creates a VRF, e.g.
+//
+//	vrf, err := vrfs.Create("fabric1", ndfc.VRF{Name: "VRF1", ID: 50001, TemplateConfig: ndfc.TemplateConfig{"vrfVlanId": "2001"}})
+//
+// The default templates are used if none are set. The vrfName and vrfSegmentId template settings default to Name and ID.
+func (s *VRFService) Create(fabric string, vrf VRF, mods ...func(*nd.Req)) (VRF, error) {
+	res, err := s.overlay.client.Post(s.overlay.path(fabric), vrf.prepare(fabric), mods...)
+	if err != nil {
+		return VRF{}, err
+	}
+	err = decodeRes(res, &vrf)
+	return vrf, err
+}
+
+// Update updates a VRF.
+func (s *VRFService) Update(fabric string, vrf VRF, mods ...func(*nd.Req)) (VRF, error) {
+	res, err := s.overlay.client.Put(s.overlay.path(fabric, vrf.Name), vrf.prepare(fabric), mods...)
+	if err != nil {
+		return VRF{}, err
+	}
+	err = decodeRes(res, &vrf)
+	return vrf, err
+}
+
+// Delete deletes a VRF, which must not be attached to any switch.
+func (s *VRFService) Delete(fabric, name string, mods ...func(*nd.Req)) error {
+	_, err := s.overlay.client.Delete(s.overlay.path(fabric, name), nil, mods...)
+	return err
+}
+
+// Attach attaches VRFs to switches in a single request. The attachments are deployed with Deploy.
+func (s *VRFService) Attach(ctx context.Context, fabric string, attachments ...VRFAttachment) error {
+	return s.overlay.attach(ctx, fabric, true, toAny(attachments), func(i int) string { return attachments[i].VRFName })
+}
+
+// Detach detaches VRFs from switches in a single request. The detachments are deployed with Deploy.
+func (s *VRFService) Detach(ctx context.Context, fabric string, attachments ...VRFAttachment) error {
+	return s.overlay.attach(ctx, fabric, false, toAny(attachments), func(i int) string { return attachments[i].VRFName })
+}
+
+// Attachments returns the attachment status of VRFs on all switches.
+func (s *VRFService) Attachments(fabric string, names []string, mods ...func(*nd.Req)) ([]AttachmentStatus, error) {
+	return s.overlay.attachments(fabric, names, mods...)
+}
+
+// Deploy deploys VRFs and waits until all of them are deployed, one of them failed or is missing,
+// or the Timeout of the service has elapsed or ctx is done.
+// The last observed status of the VRFs is returned in any case.
+func (s *VRFService) Deploy(ctx context.Context, fabric string, names ...string) ([]DeploymentStatus, error) {
+	return s.overlay.deploy(ctx, fabric, names, s.PollInterval, s.Timeout)
+}
+
+func (vrf VRF) prepare(fabric string) VRF {
+	vrf.Fabric = fabric
+	if vrf.Template == "" {
+		vrf.Template = "Default_VRF_Universal"
+	}
+	if vrf.ExtensionTemplate == "" {
+		vrf.ExtensionTemplate = "Default_VRF_Extension_Universal"
+	}
+	config := TemplateConfig{"vrfName": vrf.Name}
+	if vrf.ID != 0 {
+		config["vrfSegmentId"] = strconv.FormatInt(vrf.ID, 10)
+	}
+	for k, v := range vrf.TemplateConfig {
+		config[k] = v
+	}
+	vrf.TemplateConfig = config
+	vrf.Status = ""
+	return vrf
+}
+
+func toAny[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package ndfc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	nd "github.com/netascode/go-nd"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const vrfsPath = testBasePath + "/top-down/fabrics/fabric1/vrfs"
+
+// TestVRFServiceList tests the VRFService::List method.
+func TestVRFServiceList(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(testData("vrfs.json"))
+	list, err := vrfs.List("fabric1")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "VRF1", list[0].Name)
+	assert.Equal(t, "2001", list[0].TemplateConfig["vrfVlanId"])
+	assert.Equal(t, "PENDING", list[1].Status)
+}
+
+// TestVRFServiceCreate tests the VRFService::Create method.
+func TestVRFServiceCreate(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+
+	gock.New(testURL).Post(vrfsPath).
+		BodyString(`{"fabric":"fabric1","vrfName":"VRF1","vrfId":50001,"vrfTemplate":"Default_VRF_Universal",` +
+			`"vrfExtensionTemplate":"Default_VRF_Extension_Universal",` +
+			`"vrfTemplateConfig":"{\"vrfName\":\"VRF1\",\"vrfSegmentId\":\"50001\",\"vrfVlanId\":\"2001\"}"}`).
+		Reply(200).BodyString(`{"id": 1, "fabric": "fabric1", "vrfName": "VRF1", "vrfId": 50001, "vrfStatus": "NA"}`)
+	vrf, err := vrfs.Create("fabric1", VRF{Name: "VRF1", ID: 50001, TemplateConfig: TemplateConfig{"vrfVlanId": "2001"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "NA", vrf.Status)
+	assert.Equal(t, "2001", vrf.TemplateConfig["vrfVlanId"])
+}
+
+// TestVRFServiceUpdateDelete tests the VRFService::Update and VRFService::Delete methods.
+func TestVRFServiceUpdateDelete(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+
+	gock.New(testURL).Put(vrfsPath + "/VRF1").Reply(200)
+	_, err := vrfs.Update("fabric1", VRF{Name: "VRF1", ID: 50001})
+	assert.NoError(t, err)
+
+	gock.New(testURL).Delete(vrfsPath + "/VRF1").Reply(500).BodyString(`{"message": "VRF is attached"}`)
+	err = vrfs.Delete("fabric1", "VRF1", nd.NoLogPayload)
+	assert.ErrorContains(t, err, "VRF is attached")
+}
+
+// TestVRFServiceAttach tests the VRFService::Attach and VRFService::Detach methods.
+func TestVRFServiceAttach(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+
+	gock.New(testURL).Post(vrfsPath + "/attachments").
+		BodyString(`[{"vrfName":"VRF1","lanAttachList":[` +
+			`{"serialNumber":"A","vlan":2001,"freeformConfig":"","extensionValues":"","instanceValues":"","fabric":"fabric1","vrfName":"VRF1","deployment":true},` +
+			`{"serialNumber":"B","freeformConfig":"","extensionValues":"","instanceValues":"","fabric":"fabric1","vrfName":"VRF1","deployment":true}]},` +
+			`{"vrfName":"VRF2","lanAttachList":[` +
+			`{"serialNumber":"A","freeformConfig":"","extensionValues":"","instanceValues":"","fabric":"fabric1","vrfName":"VRF2","deployment":true}]}]`).
+		Reply(200)
+	err := vrfs.Attach(context.Background(), "fabric1",
+		VRFAttachment{VRFName: "VRF1", SerialNumber: "A", Vlan: 2001},
+		VRFAttachment{VRFName: "VRF2", SerialNumber: "A"},
+		VRFAttachment{VRFName: "VRF1", SerialNumber: "B"})
+	assert.NoError(t, err)
+
+	gock.New(testURL).Post(vrfsPath + "/attachments").
+		BodyString(`[{"vrfName":"VRF1","lanAttachList":[` +
+			`{"serialNumber":"A","freeformConfig":"","extensionValues":"","instanceValues":"","fabric":"fabric1","vrfName":"VRF1","deployment":false}]}]`).
+		Reply(200)
+	err = vrfs.Detach(context.Background(), "fabric1", VRFAttachment{VRFName: "VRF1", SerialNumber: "A"})
+	assert.NoError(t, err)
+}
+
+// TestVRFServiceAttachments tests the VRFService::Attachments method.
+func TestVRFServiceAttachments(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+
+	gock.New(testURL).Get(vrfsPath+"/attachments").MatchParam("vrf-names", "VRF1").Reply(200).BodyString(testData("vrf-attachments.json"))
+	attachments, err := vrfs.Attachments("fabric1", []string{"VRF1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []AttachmentStatus{
+		{Name: "VRF1", SerialNumber: "9A1B2C3D4E5", SwitchName: "leaf1", State: "DEPLOYED", Attached: true, Vlan: 2001},
+		{Name: "VRF1", SerialNumber: "9F8E7D6C5B4", SwitchName: "leaf2", State: "NA", Attached: false, Vlan: 2001},
+	}, attachments)
+}
+
+// TestVRFServiceDeploy tests the VRFService::Deploy method.
+func TestVRFServiceDeploy(t *testing.T) {
+	defer gock.Off()
+	vrfs := NewVRFService(testClient())
+	vrfs.PollInterval = time.Millisecond
+
+	// Success
+	gock.New(testURL).Post(vrfsPath + "/deployments").BodyString(`{"vrfNames":"VRF1,VRF2"}`).Reply(200)
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(testData("vrfs.json"))
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(`[{"vrfName": "VRF1", "vrfStatus": "DEPLOYED"}, {"vrfName": "VRF2", "vrfStatus": "DEPLOYED"}]`)
+	statuses, err := vrfs.Deploy(context.Background(), "fabric1", "VRF1", "VRF2")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeploymentStatus{{Name: "VRF1", Status: "DEPLOYED"}, {Name: "VRF2", Status: "DEPLOYED"}}, statuses)
+
+	// Failure
+	gock.New(testURL).Post(vrfsPath + "/deployments").Reply(200)
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(`[{"vrfName": "VRF1", "vrfStatus": "FAILED"}]`)
+	statuses, err = vrfs.Deploy(context.Background(), "fabric1", "VRF1")
+	failedErr := &nd.WaitFailedError{}
+	assert.True(t, errors.As(err, &failedErr))
+	assert.Equal(t, "FAILED", statuses[0].Status)
+
+	// Timeout
+	gock.New(testURL).Post(vrfsPath + "/deployments").Reply(200)
+	gock.New(testURL).Get(vrfsPath).Persist().Reply(200).BodyString(testData("vrfs.json"))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	statuses, err = vrfs.Deploy(ctx, "fabric1", "VRF2")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "PENDING", statuses[0].Status)
+	gock.Off()
+
+	// Timeout of the service
+	vrfs.Timeout = 20 * time.Millisecond
+	gock.New(testURL).Post(vrfsPath + "/deployments").Reply(200)
+	gock.New(testURL).Get(vrfsPath).Persist().Reply(200).BodyString(`[{"vrfName": "VRF1", "vrfStatus": "NA"}]`)
+	statuses, err = vrfs.Deploy(context.Background(), "fabric1", "VRF1")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "NA", statuses[0].Status)
+	vrfs.Timeout = 0
+	gock.Off()
+
+	// Missing VRF
+	gock.New(testURL).Post(vrfsPath + "/deployments").Reply(200)
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(`[{"vrfName": "VRF1", "vrfStatus": "deployed"}]`)
+	_, err = vrfs.Deploy(context.Background(), "fabric1", "VRF1", "VRF3")
+	assert.ErrorContains(t, err, "vrf VRF3 not found in fabric fabric1")
+	assert.True(t, errors.As(err, &failedErr))
+	assert.True(t, gock.IsDone())
+
+	// Status is compared case-insensitively
+	gock.New(testURL).Post(vrfsPath + "/deployments").Reply(200)
+	gock.New(testURL).Get(vrfsPath).Reply(200).BodyString(`[{"vrfName": "VRF1", "vrfStatus": "deployed"}]`)
+	statuses, err = vrfs.Deploy(context.Background(), "fabric1", "VRF1")
+	assert.NoError(t, err)
+	assert.Equal(t, "deployed", statuses[0].Status)
+}
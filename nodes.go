@@ -0,0 +1,163 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// NodeSelection is the strategy used to select a Nexus Dashboard cluster node for requests.
+type NodeSelection int
+
+const (
+	// PrimarySecondary sends all requests to the first available node, starting with the primary node,
+	// and only fails over to the next node on connection errors.
+	PrimarySecondary NodeSelection = iota
+	// RoundRobin distributes requests across all nodes.
+	RoundRobin
+)
+
+// Nodes adds further Nexus Dashboard cluster nodes, e.g. https://10.0.0.2, to fail over to on connection errors.
+// The URL passed to NewClient is the primary node.
+//
+//	client, _ := nd.NewClient("https://10.0.0.1", "/appcenter/cisco/ndfc/api/v1", "user", "password", "", true,
+//		nd.Nodes("https://10.0.0.2", "https://10.0.0.3"))
+func Nodes(urls ...string) func(*Client) {
+	return func(client *Client) {
+		client.nodes.mutex.Lock()
+		defer client.nodes.mutex.Unlock()
+		client.nodes.urls = append(client.nodes.urls, urls...)
+	}
+}
+
+// NodeStrategy modifies the node selection strategy from the default of PrimarySecondary.
+func NodeStrategy(x NodeSelection) func(*Client) {
+	return func(client *Client) {
+		client.nodes.mutex.Lock()
+		defer client.nodes.mutex.Unlock()
+		client.nodes.strategy = x
+	}
+}
+
+// NodeLocalAuth determines whether the password login is repeated when failing over to another node.
+// By default tokens are considered valid cluster-wide. NewClient fails if combined with the RoundRobin strategy.
+func NodeLocalAuth(x bool) func(*Client) {
+	return func(client *Client) {
+		client.nodes.mutex.Lock()
+		defer client.nodes.mutex.Unlock()
+		client.nodes.localAuth = x
+	}
+}
+
+// nodePool holds the further cluster nodes shared by all copies of a client.
+// The primary node is always the Url of the client, so that changes of the field take effect.
+type nodePool struct {
+	mutex     sync.Mutex
+	urls      []string
+	strategy  NodeSelection
+	localAuth bool
+	// current is the active node with PrimarySecondary and the next node with RoundRobin, 0 being the primary node
+	current int
+}
+
+// node returns the node at index i, the primary node being at index 0. The mutex must be held.
+func (p *nodePool) node(primary string, i int) string {
+	i %= len(p.urls) + 1
+	if i == 0 {
+		return primary
+	}
+	return p.urls[i-1]
+}
+
+// size returns the number of nodes including the primary node.
+func (p *nodePool) size() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.urls) + 1
+}
+
+// active returns the node used for authentication requests.
+func (p *nodePool) active(primary string) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.node(primary, p.current)
+}
+
+// pick returns the node for the next request.
+func (p *nodePool) pick(primary string) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	node := p.node(primary, p.current)
+	if p.strategy == RoundRobin {
+		p.current = (p.current + 1) % (len(p.urls) + 1)
+	}
+	return node
+}
+
+// failover switches away from the failed node and reports whether another node is available.
+func (p *nodePool) failover(primary, failed string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.urls) == 0 {
+		return false
+	}
+	if p.node(primary, p.current) == failed {
+		p.current = (p.current + 1) % (len(p.urls) + 1)
+	}
+	return true
+}
+
+// setNode directs the request to the given node.
+func setNode(req *http.Request, node string) {
+	u, err := url.Parse(node)
+	if err != nil || u.Host == "" {
+		return
+	}
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	req.Host = ""
+}
+
+// dialError reports whether err occurred while connecting, i.e. before the request was sent.
+func dialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// failover switches to the next node after a connection error and reports whether the request should be resent immediately.
+// With NodeLocalAuth the client logs in to the next node first.
+func (client *Client) failover(ctx context.Context, req *Req, node string, err error) bool {
+	if !(idempotent(req) || dialError(err)) || !client.nodes.failover(client.Url, node) {
+		return false
+	}
+	client.nodes.mutex.Lock()
+	localAuth := client.nodes.localAuth
+	client.nodes.mutex.Unlock()
+	if auth, ok := client.Auth.(*PasswordAuth); ok && localAuth {
+		auth.set("", time.Time{})
+		if err := client.AuthenticateContext(ctx); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// send sends an authentication request to the active node, failing over to the other nodes on connection errors.
+func (client *Client) send(req *http.Request) (*http.Response, error) {
+	for i := 0; ; i++ {
+		node := client.nodes.active(client.Url)
+		setNode(req, node)
+		httpRes, err := client.HttpClient.Do(req)
+		if err == nil || i >= client.nodes.size()-1 || !client.nodes.failover(client.Url, node) {
+			return httpRes, err
+		}
+		client.Logger.WarnContext(req.Context(), "HTTP connection failed, failing over to next node", "node", node, "error", err)
+		if req.GetBody != nil {
+			req.Body, _ = req.GetBody()
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package nd
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const (
+	testURL2 = "https://10.0.0.2"
+	testURL3 = "https://10.0.0.3"
+)
+
+var errDial = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+// TestNodesFailover tests the failover to other cluster nodes.
+func TestNodesFailover(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	Nodes(testURL2, testURL3)(&client)
+
+	// Connection error fails over to the next node
+	gock.New(testURL).Get("/url").ReplyError(errors.New("fail"))
+	gock.New(testURL2).Get("/url").Reply(200)
+	res, err := client.DoFull(client.NewReq("GET", "/url", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Attempts)
+	assert.True(t, gock.IsDone())
+
+	// Subsequent requests stick to the new node
+	gock.New(testURL2).Post("/url").Reply(200)
+	_, err = client.Post("/url", "{}")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// POST only fails over if the request was not sent
+	gock.New(testURL2).Post("/url").ReplyError(errors.New("fail"))
+	_, err = client.Post("/url", "{}")
+	assert.Error(t, err)
+	gock.New(testURL2).Post("/url").ReplyError(errDial)
+	gock.New(testURL3).Post("/url").Reply(200)
+	_, err = client.Post("/url", "{}")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// All nodes down
+	gock.New(testURL3).Get("/url").ReplyError(errDial)
+	gock.New(testURL).Get("/url").ReplyError(errDial)
+	gock.New(testURL2).Get("/url").ReplyError(errDial)
+	res, err = client.DoFull(client.NewReq("GET", "/url", nil))
+	assert.Error(t, err)
+	assert.Equal(t, 3, res.Attempts)
+}
+
+// TestNodesPrimaryUrl tests that the primary node follows the Url field of the client.
+func TestNodesPrimaryUrl(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.Url = testURL3
+
+	gock.New(testURL3).Get("/url").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// Further nodes are kept
+	Nodes(testURL2)(&client)
+	gock.New(testURL3).Get("/url").ReplyError(errDial)
+	gock.New(testURL2).Get("/url").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestNodesRoundRobin tests the RoundRobin node selection strategy.
+func TestNodesRoundRobin(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	Nodes(testURL2)(&client)
+	NodeStrategy(RoundRobin)(&client)
+
+	gock.New(testURL).Get("/url").Reply(200)
+	gock.New(testURL2).Get("/url").Reply(200)
+	gock.New(testURL).Get("/url").Reply(200)
+	for i := 0; i < 3; i++ {
+		_, err := client.Get("/url")
+		assert.NoError(t, err)
+	}
+	assert.True(t, gock.IsDone())
+}
+
+// TestNodesAuthentication tests authentication with multiple cluster nodes.
+func TestNodesAuthentication(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+	Nodes(testURL2)(&client)
+	NodeLocalAuth(true)(&client)
+
+	// Login fails over to the next node
+	gock.New(testURL).Post("/login").ReplyError(errDial)
+	gock.New(testURL2).Post("/login").Reply(200).BodyString(`{"token": "NODE2"}`)
+	gock.New(testURL2).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{}`)
+	gock.New(testURL2).Get("/url").MatchHeader("Authorization", "Bearer NODE2").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// Request failover logs in to the next node
+	client.Auth.(*PasswordAuth).set("NODE2", time.Now())
+	gock.New(testURL2).Get("/url").ReplyError(errDial)
+	gock.New(testURL).Post("/login").Reply(200).BodyString(`{"token": "NODE1"}`)
+	gock.New(testURL).Get("/api/config/dn/apigwcfg/default").Reply(200).BodyString(`{}`)
+	gock.New(testURL).Get("/url").MatchHeader("Authorization", "Bearer NODE1").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestNodeLocalAuthRoundRobin tests that NodeLocalAuth cannot be combined with RoundRobin.
+func TestNodeLocalAuthRoundRobin(t *testing.T) {
+	_, err := NewClient(testURL, "/", "usr", "pwd", "", true, Nodes(testURL2), NodeStrategy(RoundRobin), NodeLocalAuth(true))
+	assert.Error(t, err)
+	_, err = NewClient(testURL, "/", "usr", "pwd", "", true, Nodes(testURL2), NodeLocalAuth(true))
+	assert.NoError(t, err)
+}
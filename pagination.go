@@ -0,0 +1,180 @@
+package nd
+
+import (
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// DefaultPageSize is the number of items requested per page if the paginator does not specify it.
+const DefaultPageSize int = 100
+
+// Paginator describes how a list endpoint is paginated.
+type Paginator interface {
+	// Page returns the query parameters requesting the page with the given zero-based index.
+	Page(page int) url.Values
+	// Items returns the items of a page and whether further pages follow.
+	Items(res Res, page int) ([]Res, bool)
+}
+
+// OffsetLimit paginates with offset and limit query parameters, e.g. ?offset=100&limit=100.
+type OffsetLimit struct {
+	// Limit is the number of items per page, DefaultPageSize if zero.
+	Limit int
+	// OffsetParam is the name of the offset query parameter, "offset" if empty.
+	OffsetParam string
+	// LimitParam is the name of the limit query parameter, "limit" if empty.
+	LimitParam string
+	// ItemsPath is the GJSON path of the items array. If empty, the response itself or its "items" field is used.
+	ItemsPath string
+	// TotalPath is the GJSON path of the total number of items, e.g. "meta.total".
+	// If empty or missing, pagination stops at the first page with less than Limit items.
+	TotalPath string
+}
+
+// Page returns the offset and limit query parameters.
+func (p *OffsetLimit) Page(page int) url.Values {
+	limit := pageSize(p.Limit)
+	return url.Values{
+		paramName(p.OffsetParam, "offset"): {strconv.Itoa(page * limit)},
+		paramName(p.LimitParam, "limit"):   {strconv.Itoa(limit)},
+	}
+}
+
+// Items returns the items of a page and whether further pages follow.
+func (p *OffsetLimit) Items(res Res, page int) ([]Res, bool) {
+	return pageItems(res, page, pageSize(p.Limit), p.ItemsPath, p.TotalPath)
+}
+
+// PageSize paginates with page number and page size query parameters, e.g. ?page=1&size=100.
+type PageSize struct {
+	// Size is the number of items per page, DefaultPageSize if zero.
+	Size int
+	// FirstPage is the number of the first page, usually 0 or 1.
+	FirstPage int
+	// PageParam is the name of the page query parameter, "page" if empty.
+	PageParam string
+	// SizeParam is the name of the page size query parameter, "size" if empty.
+	SizeParam string
+	// ItemsPath is the GJSON path of the items array. If empty, the response itself or its "items" field is used.
+	ItemsPath string
+	// TotalPath is the GJSON path of the total number of items, e.g. "meta.total".
+	// If empty or missing, pagination stops at the first page with less than Size items.
+	TotalPath string
+}
+
+// Page returns the page and size query parameters.
+func (p *PageSize) Page(page int) url.Values {
+	return url.Values{
+		paramName(p.PageParam, "page"): {strconv.Itoa(p.FirstPage + page)},
+		paramName(p.SizeParam, "size"): {strconv.Itoa(pageSize(p.Size))},
+	}
+}
+
+// Items returns the items of a page and whether further pages follow.
+func (p *PageSize) Items(res Res, page int) ([]Res, bool) {
+	return pageItems(res, page, pageSize(p.Size), p.ItemsPath, p.TotalPath)
+}
+
+func pageSize(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+	return size
+}
+
+func paramName(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// pageItems extracts the items of a page and determines whether further pages follow,
+// either by comparing with the total number of items or by checking for a full page.
+// Pagination stops if the page contains more items than requested.
+func pageItems(res Res, page, size int, itemsPath, totalPath string) ([]Res, bool) {
+	var items []Res
+	switch {
+	case itemsPath != "":
+		items = res.Get(itemsPath).Array()
+	case res.IsArray():
+		items = res.Array()
+	default:
+		items = res.Get("items").Array()
+	}
+	// More items than requested means the endpoint ignores the paging parameters and returned everything
+	if len(items) == 0 || len(items) > size {
+		return items, false
+	}
+	if totalPath != "" {
+		if total := res.Get(totalPath); total.Exists() {
+			return items, int64(page*size+len(items)) < total.Int()
+		}
+	}
+	return items, len(items) == size
+}
+
+// Iterate makes GET requests for all pages of a paginated list endpoint and yields the individual items, e.g.
+//
+//	for item, err := range client.Iterate("/api/v1/manage/fabrics", &nd.OffsetLimit{TotalPath: "meta.total"}) {
+//		if err != nil {
+//			return err
+//		}
+//		println(item.Get("name").String())
+//	}
+//
+// A nil paginator uses OffsetLimit with the default settings.
+// Iteration stops if a page repeats the previous page, i.e. the endpoint ignores the paging parameters.
+// Iteration stops at the first error, which is yielded together with an empty result.
+func (client *Client) Iterate(path string, paginator Paginator, mods ...func(*Req)) iter.Seq2[Res, error] {
+	if paginator == nil {
+		paginator = &OffsetLimit{}
+	}
+	return func(yield func(Res, error) bool) {
+		var previous []Res
+		for page := 0; ; page++ {
+			res, err := client.Get(path, append(mods, queryParams(paginator.Page(page)))...)
+			if err != nil {
+				yield(Res{}, err)
+				return
+			}
+			items, more := paginator.Items(res, page)
+			// A repeated page means the endpoint ignores the paging parameters
+			if page > 0 && samePage(items, previous) {
+				return
+			}
+			previous = items
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// samePage reports whether two pages contain the same items.
+func samePage(a, b []Res) bool {
+	return len(a) > 0 && slices.EqualFunc(a, b, func(x, y Res) bool { return x.Raw == y.Raw })
+}
+
+// GetAll makes GET requests for all pages of a paginated list endpoint and returns all items as a single array.
+// A nil paginator uses OffsetLimit with the default settings.
+func (client *Client) GetAll(path string, paginator Paginator, mods ...func(*Req)) (Res, error) {
+	var items []string
+	for item, err := range client.Iterate(path, paginator, mods...) {
+		if err != nil {
+			return Res{}, err
+		}
+		items = append(items, item.Raw)
+	}
+	return gjson.Parse("[" + strings.Join(items, ",") + "]"), nil
+}
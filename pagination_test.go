@@ -0,0 +1,86 @@
+package nd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientIterate tests the Client::Iterate method.
+func TestClientIterate(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Offset and limit with total
+	gock.New(testURL).Get("/url").MatchParams(map[string]string{"offset": "0", "limit": "2", "filter": "a b"}).
+		Reply(200).BodyString(`{"items": [{"id": 1}, {"id": 2}], "meta": {"total": 3}}`)
+	gock.New(testURL).Get("/url").MatchParams(map[string]string{"offset": "2", "limit": "2", "filter": "a b"}).
+		Reply(200).BodyString(`{"items": [{"id": 3}], "meta": {"total": 3}}`)
+	var ids []int64
+	for item, err := range client.Iterate("/url?filter=a+b", &OffsetLimit{Limit: 2, TotalPath: "meta.total"}) {
+		assert.NoError(t, err)
+		ids = append(ids, item.Get("id").Int())
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.True(t, gock.IsDone())
+
+	// Stop early
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`[{"id": 1}, {"id": 2}]`)
+	for item := range client.Iterate("/url", &OffsetLimit{Limit: 2}) {
+		assert.Equal(t, int64(1), item.Get("id").Int())
+		break
+	}
+	assert.True(t, gock.IsDone())
+
+	// Paging parameters ignored
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`[{"id": 1}, {"id": 2}, {"id": 3}]`)
+	ids = nil
+	for item, err := range client.Iterate("/url", &OffsetLimit{Limit: 2}) {
+		assert.NoError(t, err)
+		ids = append(ids, item.Get("id").Int())
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.True(t, gock.IsDone())
+
+	// Paging parameters ignored with full pages
+	gock.New(testURL).Get("/url").Times(2).Reply(200).BodyString(`[{"id": 1}, {"id": 2}]`)
+	ids = nil
+	for item, err := range client.Iterate("/url", &OffsetLimit{Limit: 2}) {
+		assert.NoError(t, err)
+		ids = append(ids, item.Get("id").Int())
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.True(t, gock.IsDone())
+
+	// Error
+	gock.New(testURL).Get("/url").ReplyError(errors.New("fail"))
+	for _, err := range client.Iterate("/url", nil) {
+		assert.Error(t, err)
+	}
+}
+
+// TestClientGetAll tests the Client::GetAll method.
+func TestClientGetAll(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Page and size with full pages
+	gock.New(testURL).Get("/url").MatchParams(map[string]string{"page": "1", "size": "2"}).
+		Reply(200).BodyString(`{"data": [{"id": 1}, {"id": 2}]}`)
+	gock.New(testURL).Get("/url").MatchParams(map[string]string{"page": "2", "size": "2"}).
+		Reply(200).BodyString(`{"data": [{"id": 3}, {"id": 4}]}`)
+	gock.New(testURL).Get("/url").MatchParams(map[string]string{"page": "3", "size": "2"}).
+		Reply(200).BodyString(`{"data": []}`)
+	res, err := client.GetAll("/url", &PageSize{Size: 2, FirstPage: 1, ItemsPath: "data"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), res.Get("#").Int())
+	assert.Equal(t, int64(3), res.Get("2.id").Int())
+	assert.True(t, gock.IsDone())
+
+	// Error
+	gock.New(testURL).Get("/url").Reply(500)
+	_, err = client.GetAll("/url", nil)
+	assert.Error(t, err)
+}
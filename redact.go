@@ -0,0 +1,183 @@
+package nd
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// Redacted is the placeholder which replaces sensitive values in logged payloads and headers.
+const Redacted = "<redacted>"
+
+// DefaultRedactKeys are the JSON keys whose values are redacted in logged payloads by default.
+// Keys are matched case-insensitively against any part of a JSON key name, e.g. "password" also matches "switchPassword".
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"community",
+	"apikey",
+	"api_key",
+	"authkey",
+	"auth_key",
+	"privkey",
+	"priv_key",
+	"radiuskey",
+	"radius_key",
+	"tacacskey",
+	"tacacs_key",
+}
+
+// DefaultRedactHeaders are the HTTP headers whose values are redacted in logs by default.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Nd-Apikey",
+}
+
+// Redactor removes sensitive values from logged payloads and headers.
+// Use nd.NewRedactor to create a Redactor with the default keys and headers.
+type Redactor struct {
+	// Keys are JSON key names (or parts thereof) whose values are redacted at any nesting level.
+	Keys []string
+	// Paths are SJSON paths whose values are redacted, e.g. "nvPairs.SNMP_SERVER_HOST_TRAP".
+	Paths []string
+	// Patterns are regular expressions applied to the payload, every match is replaced.
+	// Patterns are also applied to non-JSON payloads.
+	Patterns []*regexp.Regexp
+	// Headers are HTTP header names whose values are redacted.
+	Headers []string
+}
+
+// NewRedactor creates a new Redactor with the default keys and headers.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		Keys:    append([]string{}, DefaultRedactKeys...),
+		Headers: append([]string{}, DefaultRedactHeaders...),
+	}
+}
+
+// RedactKeys adds JSON keys whose values are redacted in logged payloads.
+func RedactKeys(keys ...string) func(*Client) {
+	return func(client *Client) {
+		client.redactor().Keys = append(client.redactor().Keys, keys...)
+	}
+}
+
+// RedactPaths adds SJSON paths whose values are redacted in logged payloads.
+func RedactPaths(paths ...string) func(*Client) {
+	return func(client *Client) {
+		client.redactor().Paths = append(client.redactor().Paths, paths...)
+	}
+}
+
+// RedactPatterns adds regular expressions whose matches are redacted in logged payloads.
+func RedactPatterns(patterns ...*regexp.Regexp) func(*Client) {
+	return func(client *Client) {
+		client.redactor().Patterns = append(client.redactor().Patterns, patterns...)
+	}
+}
+
+// RedactHeaders adds HTTP headers whose values are redacted in logs.
+func RedactHeaders(headers ...string) func(*Client) {
+	return func(client *Client) {
+		client.redactor().Headers = append(client.redactor().Headers, headers...)
+	}
+}
+
+func (client *Client) redactor() *Redactor {
+	if client.Redactor == nil {
+		client.Redactor = NewRedactor()
+	}
+	return client.Redactor
+}
+
+// Redact returns the payload with all sensitive values replaced.
+// A nil Redactor returns the payload unchanged.
+func (r *Redactor) Redact(payload []byte) string {
+	if r == nil || len(payload) == 0 {
+		return string(payload)
+	}
+	s := string(payload)
+	if gjson.ValidBytes(payload) {
+		if len(r.Keys) > 0 {
+			var sb strings.Builder
+			r.redactKeys(gjson.ParseBytes(payload), &sb)
+			s = sb.String()
+		}
+		for _, path := range r.Paths {
+			if gjson.Get(s, path).Exists() {
+				s, _ = sjson.Set(s, path, Redacted)
+			}
+		}
+	}
+	for _, pattern := range r.Patterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// redactKeys writes the JSON value to sb, replacing the values of sensitive keys.
+func (r *Redactor) redactKeys(value gjson.Result, sb *strings.Builder) {
+	switch {
+	case value.IsObject():
+		sb.WriteByte('{')
+		first := true
+		value.ForEach(func(key, value gjson.Result) bool {
+			if !first {
+				sb.WriteByte(',')
+			}
+			first = false
+			sb.WriteString(key.Raw)
+			sb.WriteByte(':')
+			if r.sensitiveKey(key.String()) && value.Type != gjson.Null {
+				sb.WriteString(`"` + Redacted + `"`)
+			} else {
+				r.redactKeys(value, sb)
+			}
+			return true
+		})
+		sb.WriteByte('}')
+	case value.IsArray():
+		sb.WriteByte('[')
+		for i, item := range value.Array() {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			r.redactKeys(item, sb)
+		}
+		sb.WriteByte(']')
+	default:
+		sb.WriteString(value.Raw)
+	}
+}
+
+func (r *Redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.Keys {
+		if k != "" && strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeader returns a copy of the header with the values of sensitive headers replaced.
+// A nil Redactor returns the header unchanged.
+func (r *Redactor) RedactHeader(header http.Header) http.Header {
+	if r == nil {
+		return header
+	}
+	redacted := header.Clone()
+	for _, name := range r.Headers {
+		if values := redacted.Values(name); len(values) > 0 {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,63 @@
+package nd
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestRedact tests the Redactor::Redact method.
+func TestRedact(t *testing.T) {
+	r := NewRedactor()
+	r.Paths = []string{"nvPairs.SNMP_SERVER"}
+	r.Patterns = []*regexp.Regexp{regexp.MustCompile(`ssh-rsa [A-Za-z0-9+/=]+`)}
+
+	payload := `{"userName":"admin","userPasswd":"pwd","nested":[{"switchPassword":"x","ip":"10.0.0.1"}],"nvPairs":{"SNMP_SERVER":"public","BANNER":"ssh-rsa AAAA"},"token":null}`
+	res := Body{Str: r.Redact([]byte(payload))}.Res()
+	assert.Equal(t, "admin", res.Get("userName").String())
+	assert.Equal(t, Redacted, res.Get("userPasswd").String())
+	assert.Equal(t, Redacted, res.Get("nested.0.switchPassword").String())
+	assert.Equal(t, "10.0.0.1", res.Get("nested.0.ip").String())
+	assert.Equal(t, Redacted, res.Get("nvPairs.SNMP_SERVER").String())
+	assert.Equal(t, Redacted, res.Get("nvPairs.BANNER").String())
+	assert.Equal(t, "null", res.Get("token").Raw)
+
+	// Non-JSON payloads
+	assert.Equal(t, "key "+Redacted, r.Redact([]byte("key ssh-rsa AAAA")))
+
+	// Nil Redactor
+	var nilRedactor *Redactor
+	assert.Equal(t, payload, nilRedactor.Redact([]byte(payload)))
+}
+
+// TestRedactHeader tests the Redactor::RedactHeader method.
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer ABC")
+	header.Set("Content-Type", "application/json")
+	redacted := NewRedactor().RedactHeader(header)
+	assert.Equal(t, Redacted, redacted.Get("Authorization"))
+	assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+	assert.Equal(t, "Bearer ABC", header.Get("Authorization"))
+}
+
+// TestClientRedaction tests the redaction of logged payloads.
+func TestClientRedaction(t *testing.T) {
+	defer gock.Off()
+	var buf bytes.Buffer
+	client := authenticatedTestClient()
+	Logger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))(&client)
+	RedactKeys("ROUTING_KEY")(&client)
+
+	gock.New(testURL).Post("/url").Reply(200).BodyString(`{"token": "SECRET1"}`)
+	_, err := client.Post("/url", `{"password": "SECRET2", "ROUTING_KEY": "SECRET3", "name": "abc"}`)
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), "SECRET")
+	assert.NotContains(t, buf.String(), "Bearer ABC")
+	assert.Contains(t, buf.String(), "abc")
+}
@@ -1,25 +1,69 @@
 package nd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 )
 
 // Body wraps SJSON for building JSON body strings.
+// Errors are recorded and returned by Err, Client functions refuse to send a Body with an error.
 // Usage example:
 //
-//	Body{}.Set("name", "ABC").Str
+//	Body{}.Set("name", "ABC").SetInt("vlan", 10).Str
 type Body struct {
 	Str string
+	err error
 }
 
 // Set sets a JSON path to a value.
 func (body Body) Set(path, value string) Body {
-	res, _ := sjson.Set(body.Str, path, value)
-	body.Str = res
-	return body
+	res, err := sjson.Set(body.Str, path, value)
+	return body.update(res, err)
+}
+
+// SetInt sets a JSON path to an integer value.
+func (body Body) SetInt(path string, value int64) Body {
+	res, err := sjson.Set(body.Str, path, value)
+	return body.update(res, err)
+}
+
+// SetFloat sets a JSON path to a floating point value.
+func (body Body) SetFloat(path string, value float64) Body {
+	res, err := sjson.Set(body.Str, path, value)
+	return body.update(res, err)
+}
+
+// SetBool sets a JSON path to a boolean value.
+func (body Body) SetBool(path string, value bool) Body {
+	res, err := sjson.Set(body.Str, path, value)
+	return body.update(res, err)
+}
+
+// SetAny sets a JSON path to an arbitrary value, which is marshaled to JSON, e.g. a struct, slice or map.
+func (body Body) SetAny(path string, value any) Body {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return body.update(body.Str, err)
+	}
+	return body.SetRaw(path, string(raw))
+}
+
+// Append appends an arbitrary value, which is marshaled to JSON, to the array at a JSON path.
+// The array is created if it does not exist. An empty path appends to a top-level array.
+func (body Body) Append(path string, value any) Body {
+	if path == "" {
+		return body.SetAny("-1", value)
+	}
+	return body.SetAny(path+".-1", value)
 }
 
 // SetRaw sets a JSON path to a raw string value.
@@ -27,14 +71,28 @@ func (body Body) Set(path, value string) Body {
 //
 //	Body{}.SetRaw("children", Body{}.Set("name", "New").Str).Str
 func (body Body) SetRaw(path, rawValue string) Body {
-	res, _ := sjson.SetRaw(body.Str, path, rawValue)
-	body.Str = res
-	return body
+	res, err := sjson.SetRaw(body.Str, path, rawValue)
+	return body.update(res, err)
 }
 
 // Delete deletes a JSON path.
 func (body Body) Delete(path string) Body {
-	res, _ := sjson.Delete(body.Str, path)
+	res, err := sjson.Delete(body.Str, path)
+	return body.update(res, err)
+}
+
+// Err returns the first error encountered while building the body, if any.
+func (body Body) Err() error {
+	return body.err
+}
+
+func (body Body) update(res string, err error) Body {
+	if err != nil {
+		if body.err == nil {
+			body.err = fmt.Errorf("building body failed: %w", err)
+		}
+		return body
+	}
 	body.Str = res
 	return body
 }
@@ -44,12 +102,206 @@ func (body Body) Res() Res {
 	return gjson.Parse(body.Str)
 }
 
+// PatchBuilder builds RFC 6902 JSON Patch documents, e.g. for ND Orchestrator schema updates.
+// Paths are JSON Pointers, use Pointer to build them from unescaped tokens.
+// Usage example:
+//
+//	patch := PatchBuilder{}.Replace(Pointer("templates", "0", "displayName"), "ABC")
+//	client.Patch("/mso/api/v1/schemas/"+id, patch.Str, JSONPatch)
+type PatchBuilder struct {
+	Str string
+	err error
+}
+
+// Add adds an "add" operation with a value, which is marshaled to JSON.
+func (patch PatchBuilder) Add(path string, value any) PatchBuilder {
+	return patch.opValue("add", path, value)
+}
+
+// AddRaw adds an "add" operation with a raw JSON value.
+func (patch PatchBuilder) AddRaw(path, rawValue string) PatchBuilder {
+	return patch.opRaw("add", path, rawValue)
+}
+
+// Replace adds a "replace" operation with a value, which is marshaled to JSON.
+func (patch PatchBuilder) Replace(path string, value any) PatchBuilder {
+	return patch.opValue("replace", path, value)
+}
+
+// ReplaceRaw adds a "replace" operation with a raw JSON value.
+func (patch PatchBuilder) ReplaceRaw(path, rawValue string) PatchBuilder {
+	return patch.opRaw("replace", path, rawValue)
+}
+
+// Test adds a "test" operation with a value, which is marshaled to JSON.
+func (patch PatchBuilder) Test(path string, value any) PatchBuilder {
+	return patch.opValue("test", path, value)
+}
+
+// TestRaw adds a "test" operation with a raw JSON value.
+func (patch PatchBuilder) TestRaw(path, rawValue string) PatchBuilder {
+	return patch.opRaw("test", path, rawValue)
+}
+
+// Remove adds a "remove" operation.
+func (patch PatchBuilder) Remove(path string) PatchBuilder {
+	return patch.op(Body{}.Set("op", "remove").Set("path", path))
+}
+
+// Move adds a "move" operation.
+func (patch PatchBuilder) Move(from, path string) PatchBuilder {
+	return patch.op(Body{}.Set("op", "move").Set("from", from).Set("path", path))
+}
+
+// Copy adds a "copy" operation.
+func (patch PatchBuilder) Copy(from, path string) PatchBuilder {
+	return patch.op(Body{}.Set("op", "copy").Set("from", from).Set("path", path))
+}
+
+// Len returns the number of operations.
+func (patch PatchBuilder) Len() int {
+	return int(gjson.Get(patch.Str, "#").Int())
+}
+
+// Err returns the first error encountered while marshaling values or adding invalid raw values, if any.
+func (patch PatchBuilder) Err() error {
+	return patch.err
+}
+
+// Res creates a Res object, i.e. a GJSON result object.
+func (patch PatchBuilder) Res() Res {
+	if patch.Str == "" {
+		return gjson.Parse("[]")
+	}
+	return gjson.Parse(patch.Str)
+}
+
+func (patch PatchBuilder) opValue(op, path string, value any) PatchBuilder {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return patch.fail(err)
+	}
+	return patch.opRaw(op, path, string(raw))
+}
+
+func (patch PatchBuilder) opRaw(op, path, rawValue string) PatchBuilder {
+	if !gjson.Valid(rawValue) {
+		return patch.fail(fmt.Errorf("invalid JSON value for %s operation at %q", op, path))
+	}
+	return patch.op(Body{}.Set("op", op).Set("path", path).SetRaw("value", rawValue))
+}
+
+func (patch PatchBuilder) op(op Body) PatchBuilder {
+	if op.err != nil {
+		return patch.fail(op.err)
+	}
+	str := patch.Str
+	if str == "" {
+		str = "[]"
+	}
+	str, err := sjson.SetRaw(str, "-1", op.Str)
+	if err != nil {
+		return patch.fail(err)
+	}
+	patch.Str = str
+	return patch
+}
+
+// fail records the first error encountered while building the patch.
+func (patch PatchBuilder) fail(err error) PatchBuilder {
+	if patch.err == nil {
+		patch.err = err
+	}
+	return patch
+}
+
+// Pointer builds a JSON Pointer from unescaped reference tokens, e.g. Pointer("a/b", "0") returns "/a~1b/0".
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
+
+// Diff computes a JSON Patch transforming from into to.
+// Objects are compared key by key, arrays index by index.
+// A missing to document results in a "remove" operation, a missing from document in an "add" operation.
+func Diff(from, to Res) PatchBuilder {
+	return diff(PatchBuilder{}, "", from, to)
+}
+
+func diff(patch PatchBuilder, path string, from, to Res) PatchBuilder {
+	switch {
+	case from.IsObject() && to.IsObject():
+		fromKeys := from.Map()
+		toKeys := to.Map()
+		from.ForEach(func(key, value Res) bool {
+			if _, ok := toKeys[key.String()]; !ok {
+				patch = patch.Remove(path + Pointer(key.String()))
+			}
+			return true
+		})
+		to.ForEach(func(key, value Res) bool {
+			if old, ok := fromKeys[key.String()]; ok {
+				patch = diff(patch, path+Pointer(key.String()), old, value)
+			} else {
+				patch = patch.AddRaw(path+Pointer(key.String()), value.Raw)
+			}
+			return true
+		})
+	case from.IsArray() && to.IsArray():
+		fromItems := from.Array()
+		toItems := to.Array()
+		for i := 0; i < len(fromItems) && i < len(toItems); i++ {
+			patch = diff(patch, path+Pointer(strconv.Itoa(i)), fromItems[i], toItems[i])
+		}
+		for i := len(toItems); i < len(fromItems); i++ {
+			// Remove surplus items from the end to keep the indices valid
+			patch = patch.Remove(path + Pointer(strconv.Itoa(len(fromItems)-1-i+len(toItems))))
+		}
+		for i := len(fromItems); i < len(toItems); i++ {
+			patch = patch.AddRaw(path+Pointer(strconv.Itoa(i)), toItems[i].Raw)
+		}
+	case !to.Exists():
+		if from.Exists() {
+			patch = patch.Remove(path)
+		}
+	case !from.Exists():
+		patch = patch.AddRaw(path, to.Raw)
+	case !equal(from, to):
+		patch = patch.ReplaceRaw(path, to.Raw)
+	}
+	return patch
+}
+
+// equal reports whether two scalar JSON values are equal.
+func equal(a, b Res) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case gjson.Number:
+		return a.Num == b.Num
+	case gjson.String:
+		return a.Str == b.Str
+	case gjson.JSON:
+		return a.Raw == b.Raw
+	}
+	return true
+}
+
 // Req wraps http.Request for API requests.
 type Req struct {
 	// HttpReq is the *http.Request obejct.
 	HttpReq *http.Request
 	// LogPayload indicates whether logging of payloads should be enabled.
 	LogPayload bool
+	// Idempotent indicates whether the request can safely be retried, even if it uses the POST or PATCH method.
+	Idempotent bool
+	// StrictDecode indicates whether DoInto and GetAs reject unknown fields in the response.
+	StrictDecode bool
 }
 
 // NoLogPayload prevents logging of payloads.
@@ -58,6 +310,130 @@ func NoLogPayload(req *Req) {
 	req.LogPayload = false
 }
 
+// Idempotent marks a POST or PATCH request as safe to retry after connection errors or server errors.
+func Idempotent(req *Req) {
+	req.Idempotent = true
+}
+
+// StrictDecode makes DoInto and GetAs reject responses with fields missing in the target type.
+func StrictDecode(req *Req) {
+	req.StrictDecode = true
+}
+
+// RemoveContentType removes the default Content-Type header.
 func RemoveContentType(req *Req) {
 	req.HttpReq.Header.Del("Content-Type")
 }
+
+// Query sets a URL query parameter, replacing existing values of the parameter. The value is escaped as needed, e.g.
+//
+//	res, _ := client.Get("/lan-fabric/rest/control/fabrics/msd/fabric-associations", nd.Query("fabricName", "Fabric A&B"))
+func Query(key, value string) func(*Req) {
+	return queryParams(url.Values{key: {value}})
+}
+
+// Queries sets multiple URL query parameters, replacing existing values of the parameters.
+func Queries(params map[string]string) func(*Req) {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+	return queryParams(values)
+}
+
+// Header sets an HTTP request header, replacing existing values of the header.
+func Header(key, value string) func(*Req) {
+	return func(req *Req) {
+		req.HttpReq.Header.Set(key, value)
+	}
+}
+
+// Accept sets the Accept header.
+func Accept(mime string) func(*Req) {
+	return Header("Accept", mime)
+}
+
+// ContentType replaces the default Content-Type header of application/json.
+func ContentType(mime string) func(*Req) {
+	return Header("Content-Type", mime)
+}
+
+// ContentTypeJSONPatch is the media type of RFC 6902 JSON Patch documents.
+const ContentTypeJSONPatch = "application/json-patch+json"
+
+// JSONPatch sets the Content-Type header to application/json-patch+json.
+func JSONPatch(req *Req) {
+	req.HttpReq.Header.Set("Content-Type", ContentTypeJSONPatch)
+}
+
+// requestBody returns the request body for data, which is either a JSON string or byte slice,
+// a Body, a PatchBuilder, a Res, a pointer to one of them or an arbitrary value, which is marshaled to JSON.
+// Empty data and nil pointers result in a request without body.
+func requestBody(data any) (io.Reader, error) {
+	var raw string
+	switch data := data.(type) {
+	case nil:
+	case string:
+		raw = data
+	case []byte:
+		raw = string(data)
+	case Body:
+		if data.err != nil {
+			return nil, data.err
+		}
+		raw = data.Str
+	case PatchBuilder:
+		if data.err != nil {
+			return nil, fmt.Errorf("building patch failed: %w", data.err)
+		}
+		raw = data.Res().Raw
+	case Res:
+		raw = data.Raw
+	case *Body:
+		if data != nil {
+			return requestBody(*data)
+		}
+	case *PatchBuilder:
+		if data != nil {
+			return requestBody(*data)
+		}
+	case *Res:
+		if data != nil {
+			return requestBody(*data)
+		}
+	default:
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("encoding body failed: %w", err)
+		}
+		raw = string(b)
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	return strings.NewReader(raw), nil
+}
+
+// queryParams sets the URL query parameters of the request, replacing existing values of the parameters.
+func queryParams(values url.Values) func(*Req) {
+	return func(req *Req) {
+		query := req.HttpReq.URL.Query()
+		for key, value := range values {
+			query[key] = value
+		}
+		req.HttpReq.URL.RawQuery = query.Encode()
+	}
+}
+
+// Context binds the request to ctx, e.g. to cancel it or apply a deadline:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	res, err := client.Get("/lan-fabric/rest/control/fabrics", nd.Context(ctx))
+//
+// The context also applies to the login triggered by the request and to the backoff between retries.
+func Context(ctx context.Context) func(*Req) {
+	return func(req *Req) {
+		req.HttpReq = req.HttpReq.WithContext(ctx)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 // TestSetRaw tests the Body::SetRaw method.
@@ -20,3 +21,110 @@ func TestDelete(t *testing.T) {
 	body = body.Delete("a.name")
 	assert.Equal(t, "", body.Res().Get("a.name").Str)
 }
+
+// TestQuery tests the Query and Queries modifiers.
+func TestQuery(t *testing.T) {
+	client, _ := NewClient(testURL, "/base", "usr", "pwd", "", true)
+	req := client.NewReq("GET", "/base/url?a=1", nil, Query("fabricName", "Fabric A&B/1"), Queries(map[string]string{"a": "2", "b": "c d"}))
+	assert.Equal(t, "/base/url", req.HttpReq.URL.Path)
+	assert.Equal(t, "Fabric A&B/1", req.HttpReq.URL.Query().Get("fabricName"))
+	assert.Equal(t, []string{"2"}, req.HttpReq.URL.Query()["a"])
+	assert.Equal(t, "c d", req.HttpReq.URL.Query().Get("b"))
+	assert.Equal(t, testURL+"/base/url?a=2&b=c+d&fabricName=Fabric+A%26B%2F1", req.HttpReq.URL.String())
+}
+
+// TestHeader tests the Header, Accept and ContentType modifiers.
+func TestHeader(t *testing.T) {
+	client, _ := NewClient(testURL, "/base", "usr", "pwd", "", true)
+	req := client.NewReq("GET", "/url", nil, Header("X-Test", "a"), Accept("text/plain"), ContentType("application/json-patch+json"))
+	assert.Equal(t, "a", req.HttpReq.Header.Get("X-Test"))
+	assert.Equal(t, "text/plain", req.HttpReq.Header.Get("Accept"))
+	assert.Equal(t, []string{"application/json-patch+json"}, req.HttpReq.Header.Values("Content-Type"))
+}
+
+// TestPatchBuilder tests the PatchBuilder operations.
+func TestPatchBuilder(t *testing.T) {
+	assert.Equal(t, "[]", PatchBuilder{}.Res().Raw)
+
+	patch := PatchBuilder{}.
+		Add("/a", map[string]int{"b": 1}).
+		AddRaw("/c/-", `"d"`).
+		Replace("/e", 5).
+		ReplaceRaw("/f", "true").
+		Test("/g", "h").
+		TestRaw("/i", "null").
+		Remove("/j").
+		Move("/k", "/l").
+		Copy("/m", "/n")
+	assert.NoError(t, patch.Err())
+	assert.Equal(t, 9, patch.Len())
+	assert.Equal(t, `[{"op":"add","path":"/a","value":{"b":1}},{"op":"add","path":"/c/-","value":"d"},`+
+		`{"op":"replace","path":"/e","value":5},{"op":"replace","path":"/f","value":true},`+
+		`{"op":"test","path":"/g","value":"h"},{"op":"test","path":"/i","value":null},`+
+		`{"op":"remove","path":"/j"},{"op":"move","from":"/k","path":"/l"},{"op":"copy","from":"/m","path":"/n"}]`, patch.Str)
+
+	patch = PatchBuilder{}.Add("/a", make(chan int))
+	assert.Error(t, patch.Err())
+	assert.Equal(t, 0, patch.Len())
+
+	// Invalid raw values are rejected
+	patch = PatchBuilder{}.Remove("/a").AddRaw("/b", "").ReplaceRaw("/c", "{")
+	assert.ErrorContains(t, patch.Err(), `invalid JSON value for add operation at "/b"`)
+	assert.Equal(t, `[{"op":"remove","path":"/a"}]`, patch.Str)
+}
+
+// TestPointer tests the Pointer function.
+func TestPointer(t *testing.T) {
+	assert.Equal(t, "", Pointer())
+	assert.Equal(t, "/a~1b/m~0n/0", Pointer("a/b", "m~n", "0"))
+}
+
+// TestDiff tests the Diff function.
+func TestDiff(t *testing.T) {
+	from := gjson.Parse(`{"name": "a", "num": 1, "same": {"x": [1, 2]}, "old": true, "list": [1, 2, 3], "a/b": "c"}`)
+	to := gjson.Parse(`{"name": "b", "num": 1.0, "same": {"x": [1, 2]}, "list": [1, 4], "a/b": "d", "new": {"y": null}}`)
+	patch := Diff(from, to)
+	assert.Equal(t, `[{"op":"remove","path":"/old"},{"op":"replace","path":"/name","value":"b"},`+
+		`{"op":"replace","path":"/list/1","value":4},{"op":"remove","path":"/list/2"},`+
+		`{"op":"replace","path":"/a~1b","value":"d"},{"op":"add","path":"/new","value":{"y": null}}]`, patch.Str)
+
+	list := Diff(gjson.Parse(`[1, 2, 3, 4]`), gjson.Parse(`[1]`))
+	assert.Equal(t, `[{"op":"remove","path":"/3"},{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`, list.Str)
+
+	assert.Equal(t, 0, Diff(from, from).Len())
+	assert.Equal(t, `[{"op":"replace","path":"","value":[1]}]`, Diff(from, gjson.Parse(`[1]`)).Str)
+
+	// Missing documents
+	missing := Diff(from, gjson.Result{})
+	assert.NoError(t, missing.Err())
+	assert.Equal(t, `[{"op":"remove","path":""}]`, missing.Str)
+	assert.Equal(t, `[{"op":"add","path":"","value":[1]}]`, Diff(gjson.Result{}, gjson.Parse(`[1]`)).Str)
+	assert.Equal(t, 0, Diff(gjson.Result{}, gjson.Result{}).Len())
+}
+
+// TestBodySetters tests the typed Body setters.
+func TestBodySetters(t *testing.T) {
+	body := Body{}.
+		SetInt("int", 10).
+		SetFloat("float", 1.5).
+		SetBool("bool", true).
+		SetAny("any", map[string][]int{"a": {1, 2}}).
+		Append("list", "a").
+		Append("list", 1)
+	assert.NoError(t, body.Err())
+	assert.Equal(t, `{"int":10,"float":1.5,"bool":true,"any":{"a":[1,2]},"list":["a",1]}`, body.Str)
+
+	list := Body{Str: "[]"}.Append("", 1).Append("", "a")
+	assert.NoError(t, list.Err())
+	assert.Equal(t, `[1,"a"]`, list.Str)
+}
+
+// TestBodyErr tests the error propagation of Body.
+func TestBodyErr(t *testing.T) {
+	body := Body{}.Set("a", "b").SetAny("c", make(chan int)).Set("d", "e")
+	assert.Error(t, body.Err())
+	assert.Equal(t, `{"a":"b","d":"e"}`, body.Str)
+
+	body = Body{}.Set("", "b")
+	assert.Error(t, body.Err())
+}
@@ -1,6 +1,9 @@
 package nd
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/tidwall/gjson"
 )
 
@@ -8,3 +11,21 @@ import (
 // This is a GJSON result, which offers advanced and safe parsing capabilities.
 // https://github.com/tidwall/gjson
 type Res = gjson.Result
+
+// Response is an API response including HTTP metadata, as returned by Client.DoFull.
+type Response struct {
+	// Res is the parsed response body.
+	Res Res
+	// Body is the raw response body.
+	Body []byte
+	// HttpRes is the *http.Response of the last attempt. Its body has already been read.
+	HttpRes *http.Response
+	// StatusCode is the HTTP status code of the last attempt.
+	StatusCode int
+	// Header contains the HTTP response headers of the last attempt.
+	Header http.Header
+	// Attempts is the number of attempts made, including retries.
+	Attempts int
+	// Duration is the total duration of the request, including retries and backoff.
+	Duration time.Duration
+}
@@ -0,0 +1,206 @@
+package nd
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAttempt describes a failed attempt, which is passed to a RetryPolicy.
+type RetryAttempt struct {
+	// Req is the request.
+	Req *Req
+	// Response is the HTTP response of the attempt, nil in case of a connection error.
+	Response *http.Response
+	// Err is the connection or response body error of the attempt, nil if a response was received.
+	Err error
+	// Attempt is the zero-based number of the failed attempt.
+	Attempt int
+	// LastDelay is the delay before the failed attempt, zero for the first attempt.
+	LastDelay time.Duration
+}
+
+// RetryPolicy decides whether and when failed requests are retried.
+// The maximum number of retries is limited by Client.MaxRetries independent of the policy.
+type RetryPolicy interface {
+	// ShouldRetry reports whether the failed attempt should be retried.
+	ShouldRetry(attempt RetryAttempt) bool
+	// NextDelay returns the delay before the next attempt.
+	NextDelay(attempt RetryAttempt) time.Duration
+}
+
+// Retry sets the retry policy, which replaces the default policy based on the
+// BackoffMinDelay, BackoffMaxDelay and BackoffDelayFactor settings, e.g.
+//
+//	policy := &nd.DefaultRetryPolicy{MinDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second, DelayFactor: 2, Jitter: nd.FullJitter}
+//	client, _ := nd.NewClient("https://10.1.1.1", "/appcenter/cisco/ndfc/api/v1", "user", "password", "", true, nd.Retry(policy))
+func Retry(policy RetryPolicy) func(*Client) {
+	return func(client *Client) {
+		client.RetryPolicy = policy
+	}
+}
+
+// Jitter is the strategy used to randomize backoff delays.
+type Jitter int
+
+const (
+	// EqualJitter randomizes the delay between half and the full exponential backoff delay.
+	EqualJitter Jitter = iota
+	// NoJitter uses the exponential backoff delay as is.
+	NoJitter
+	// FullJitter randomizes the delay between zero and the full exponential backoff delay.
+	FullJitter
+	// DecorrelatedJitter randomizes the delay between the minimum delay and three times the previous delay.
+	DecorrelatedJitter
+)
+
+// DefaultRetryPolicy retries connection errors and HTTP status codes 408, 429 and 501-599 with exponential backoff.
+// The Retry-After header is honored if present, up to MaxDelay.
+// Non-idempotent requests, i.e. POST and PATCH requests not marked with the Idempotent modifier,
+// are only retried if the connection could not be established or the server explicitly rejected
+// the request with HTTP status 429 or 503.
+type DefaultRetryPolicy struct {
+	// MinDelay is the minimum delay between two attempts.
+	// DecorrelatedJitter uses DefaultBackoffMinDelay seconds as base for the first delay if zero.
+	MinDelay time.Duration
+	// MaxDelay is the maximum delay between two attempts, also limiting the Retry-After delay if not zero.
+	MaxDelay time.Duration
+	// DelayFactor is the exponential backoff delay factor.
+	DelayFactor float64
+	// Jitter is the strategy used to randomize delays.
+	Jitter Jitter
+}
+
+// ShouldRetry reports whether the failed attempt should be retried.
+func (policy *DefaultRetryPolicy) ShouldRetry(attempt RetryAttempt) bool {
+	if attempt.Response == nil || attempt.Err != nil {
+		return idempotent(attempt.Req) || dialError(attempt.Err)
+	}
+	switch code := attempt.Response.StatusCode; {
+	case code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable:
+		return true
+	case code == http.StatusRequestTimeout || (code >= 501 && code <= 599):
+		return idempotent(attempt.Req)
+	}
+	return false
+}
+
+// NextDelay returns the delay requested by the Retry-After header, limited to MaxDelay, or the exponential backoff delay.
+func (policy *DefaultRetryPolicy) NextDelay(attempt RetryAttempt) time.Duration {
+	if attempt.Response != nil {
+		if delay, ok := retryAfter(attempt.Response.Header.Get("Retry-After")); ok {
+			if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+				delay = policy.MaxDelay
+			}
+			return delay
+		}
+	}
+	min := float64(policy.MinDelay)
+	max := float64(policy.MaxDelay)
+	backoff := min * math.Pow(policy.DelayFactor, float64(attempt.Attempt))
+	if backoff > max {
+		backoff = max
+	}
+	switch policy.Jitter {
+	case NoJitter:
+	case FullJitter:
+		backoff = rand.Float64() * backoff
+	case DecorrelatedJitter:
+		// The delay grows from the last delay, which requires a non-zero base
+		base := min
+		if base <= 0 {
+			base = float64(time.Duration(DefaultBackoffMinDelay) * time.Second)
+		}
+		last := float64(attempt.LastDelay)
+		if last < base {
+			last = base
+		}
+		backoff = min + rand.Float64()*(last*3-min)
+		if max > 0 && backoff > max {
+			backoff = max
+		}
+	default:
+		backoff = (rand.Float64()/2+0.5)*(backoff-min) + min
+	}
+	return time.Duration(backoff)
+}
+
+// idempotent reports whether a request can safely be sent again.
+func idempotent(req *Req) bool {
+	if req == nil {
+		return false
+	}
+	if req.Idempotent {
+		return true
+	}
+	switch req.HttpReq.Method {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	}
+	return true
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// retryPolicy returns the configured retry policy or the default policy based on the backoff settings.
+func (client *Client) retryPolicy() RetryPolicy {
+	if client.RetryPolicy != nil {
+		return client.RetryPolicy
+	}
+	return &DefaultRetryPolicy{
+		MinDelay:    time.Duration(client.BackoffMinDelay) * time.Second,
+		MaxDelay:    time.Duration(client.BackoffMaxDelay) * time.Second,
+		DelayFactor: client.BackoffDelayFactor,
+	}
+}
+
+// retry decides whether the failed attempt is retried and waits for the delay returned by the retry policy.
+// It returns false without further waiting as soon as ctx is canceled.
+func (client *Client) retry(ctx context.Context, attempt RetryAttempt) (time.Duration, bool) {
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	if attempt.Attempt >= client.MaxRetries {
+		client.Logger.Log(ctx, LevelTrace, "Maximum number of retries reached", "attempts", attempt.Attempt, "max_retries", client.MaxRetries)
+		return 0, false
+	}
+	policy := client.retryPolicy()
+	if !policy.ShouldRetry(attempt) {
+		return 0, false
+	}
+	delay := policy.NextDelay(attempt)
+	return delay, client.sleep(ctx, delay)
+}
+
+// sleep waits for the delay and returns false if ctx is canceled in the meantime.
+func (client *Client) sleep(ctx context.Context, delay time.Duration) bool {
+	client.Logger.Log(ctx, LevelTrace, "Backoff", "delay", delay.Round(time.Millisecond))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		client.Logger.Log(ctx, LevelTrace, "Backoff interrupted", "error", ctx.Err())
+		return false
+	}
+}
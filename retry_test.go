@@ -0,0 +1,100 @@
+package nd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestDefaultRetryPolicyShouldRetry tests the DefaultRetryPolicy::ShouldRetry method.
+func TestDefaultRetryPolicyShouldRetry(t *testing.T) {
+	client := testClient()
+	policy := &DefaultRetryPolicy{}
+	get := client.NewReq("GET", "/url", nil)
+	post := client.NewReq("POST", "/url", nil)
+	idempotentPost := client.NewReq("POST", "/url", nil, Idempotent)
+	status := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Header: http.Header{}}
+	}
+
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &get, Err: errors.New("fail")}))
+	assert.False(t, policy.ShouldRetry(RetryAttempt{Req: &post, Err: errors.New("fail")}))
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &idempotentPost, Err: errors.New("fail")}))
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &get, Response: status(408)}))
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &get, Response: status(502)}))
+	assert.False(t, policy.ShouldRetry(RetryAttempt{Req: &post, Response: status(502)}))
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &post, Response: status(429)}))
+	assert.True(t, policy.ShouldRetry(RetryAttempt{Req: &post, Response: status(503)}))
+	assert.False(t, policy.ShouldRetry(RetryAttempt{Req: &get, Response: status(500)}))
+	assert.False(t, policy.ShouldRetry(RetryAttempt{Req: &get, Response: status(404)}))
+}
+
+// TestDefaultRetryPolicyNextDelay tests the DefaultRetryPolicy::NextDelay method.
+func TestDefaultRetryPolicyNextDelay(t *testing.T) {
+	policy := &DefaultRetryPolicy{MinDelay: time.Second, MaxDelay: 10 * time.Second, DelayFactor: 2, Jitter: NoJitter}
+	assert.Equal(t, time.Second, policy.NextDelay(RetryAttempt{Attempt: 0}))
+	assert.Equal(t, 4*time.Second, policy.NextDelay(RetryAttempt{Attempt: 2}))
+	assert.Equal(t, 10*time.Second, policy.NextDelay(RetryAttempt{Attempt: 5}))
+
+	// Retry-After header
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, policy.NextDelay(RetryAttempt{Response: res}))
+	res.Header.Set("Retry-After", "3600")
+	assert.Equal(t, 10*time.Second, policy.NextDelay(RetryAttempt{Response: res}))
+	res.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.Equal(t, time.Duration(0), policy.NextDelay(RetryAttempt{Response: res}))
+
+	// Jitter strategies
+	for _, jitter := range []Jitter{EqualJitter, FullJitter, DecorrelatedJitter} {
+		policy.Jitter = jitter
+		for i := 0; i < 100; i++ {
+			delay := policy.NextDelay(RetryAttempt{Attempt: 2, LastDelay: 2 * time.Second})
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, 10*time.Second)
+			if jitter != FullJitter {
+				assert.GreaterOrEqual(t, delay, time.Second)
+			}
+		}
+	}
+
+	// Decorrelated jitter without minimum delay
+	policy = &DefaultRetryPolicy{Jitter: DecorrelatedJitter}
+	delay := time.Duration(0)
+	for i := 0; i < 100; i++ {
+		delay += policy.NextDelay(RetryAttempt{Attempt: 0})
+	}
+	assert.Greater(t, delay, time.Duration(0))
+}
+
+// TestClientRetry tests the retry behavior of client requests.
+func TestClientRetry(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	Retry(&DefaultRetryPolicy{})(&client)
+	client.MaxRetries = 2
+
+	// Too many requests
+	gock.New(testURL).Post("/url").Reply(429).SetHeader("Retry-After", "0")
+	gock.New(testURL).Post("/url").Reply(200)
+	res, err := client.DoFull(client.NewReq("POST", "/url", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Attempts)
+
+	// POST is not replayed after a connection error
+	gock.New(testURL).Post("/url").ReplyError(errors.New("fail"))
+	res, err = client.DoFull(client.NewReq("POST", "/url", nil))
+	assert.Error(t, err)
+	assert.Equal(t, 1, res.Attempts)
+
+	// Idempotent POST is replayed
+	gock.New(testURL).Post("/url").ReplyError(errors.New("fail"))
+	gock.New(testURL).Post("/url").Reply(200)
+	res, err = client.DoFull(client.NewReq("POST", "/url", nil, Idempotent))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Attempts)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,79 @@
+package nd
+
+import (
+	"sync"
+	"time"
+)
+
+// session holds the authentication state shared by all copies of a Client.
+type session struct {
+	// loginMutex serializes logins, so that concurrent requests trigger a single login.
+	loginMutex sync.Mutex
+	// mutex protects the fields below.
+	mutex        sync.RWMutex
+	token        string
+	timeStamp    time.Time
+	tokenTimeout time.Duration
+	lastUsed     time.Time
+	idleTimeout  time.Duration
+}
+
+// get returns the current token, the time it was obtained and its timeout.
+func (s *session) get() (string, time.Time, time.Duration) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.token, s.timeStamp, s.tokenTimeout
+}
+
+// set stores a new token obtained at timeStamp.
+func (s *session) set(token string, timeStamp time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.token = token
+	s.timeStamp = timeStamp
+	s.lastUsed = timeStamp
+}
+
+// setTimeout sets the duration after which the token is renewed.
+func (s *session) setTimeout(timeout time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokenTimeout = timeout
+}
+
+// setIdleTimeout sets the duration of inactivity after which the session is closed by Nexus Dashboard.
+func (s *session) setIdleTimeout(timeout time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.idleTimeout = timeout
+}
+
+// touch records the use of the token.
+func (s *session) touch() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastUsed = time.Now()
+}
+
+// idle reports whether the idle timeout has passed since the token was last used.
+func (s *session) idle() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.idleTimeout > 0 && time.Since(s.lastUsed) > s.idleTimeout
+}
+
+// valid reports whether a token is available and its timeout has not been reached.
+func (s *session) valid() bool {
+	token, timeStamp, timeout := s.get()
+	return token != "" && time.Since(timeStamp) <= timeout
+}
+
+// invalidate discards the token if it is still the current one.
+// Requests which were rejected with an outdated token do not discard a token renewed in the meantime.
+func (s *session) invalidate(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
@@ -0,0 +1,178 @@
+package nd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitInterval is the default interval between two requests of WaitFor.
+const DefaultWaitInterval = 5 * time.Second
+
+// WaitCondition decides when WaitFor stops polling.
+type WaitCondition struct {
+	// Success reports whether the task completed successfully, required.
+	Success func(Res) bool
+	// Failure reports whether the task failed, optional.
+	Failure func(Res) bool
+}
+
+// Match returns a predicate reporting whether the value at a GJSON path equals one of the values, e.g.
+//
+//	nd.WaitCondition{Success: nd.Match("status", "COMPLETED"), Failure: nd.Match("status", "FAILED", "ABORTED")}
+func Match(path string, values ...string) func(Res) bool {
+	return func(res Res) bool {
+		value := res.Get(path).String()
+		for _, v := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WaitOptions configures WaitFor.
+type WaitOptions struct {
+	// Interval is the initial interval between two requests, DefaultWaitInterval if not positive.
+	Interval time.Duration
+	// MaxInterval is the maximum interval if the interval is increased with a Factor greater than 1.
+	MaxInterval time.Duration
+	// Factor is the factor the interval is multiplied with after each request.
+	Factor float64
+	// Timeout limits the total duration in addition to the context, zero for no limit.
+	Timeout time.Duration
+	// Progress is called with every polled result.
+	Progress func(attempt int, res Res)
+	// Mods are applied to every request.
+	Mods []func(*Req)
+}
+
+// WaitInterval sets the interval between two requests, DefaultWaitInterval by default or if not positive.
+func WaitInterval(interval time.Duration) func(*WaitOptions) {
+	return func(opts *WaitOptions) {
+		opts.Interval = interval
+	}
+}
+
+// WaitBackoff multiplies the interval with factor after each request, up to max.
+func WaitBackoff(factor float64, max time.Duration) func(*WaitOptions) {
+	return func(opts *WaitOptions) {
+		opts.Factor = factor
+		opts.MaxInterval = max
+	}
+}
+
+// WaitTimeout limits the total duration of WaitFor.
+func WaitTimeout(timeout time.Duration) func(*WaitOptions) {
+	return func(opts *WaitOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WaitProgress sets a function which is called with every polled result, e.g. to report the progress of a task.
+func WaitProgress(fn func(attempt int, res Res)) func(*WaitOptions) {
+	return func(opts *WaitOptions) {
+		opts.Progress = fn
+	}
+}
+
+// WaitRequest sets request modifiers applied to every request, e.g. Query.
+func WaitRequest(mods ...func(*Req)) func(*WaitOptions) {
+	return func(opts *WaitOptions) {
+		opts.Mods = append(opts.Mods, mods...)
+	}
+}
+
+// WaitTimeoutError is returned by WaitFor if the timeout elapsed or the context is done before the task completed.
+type WaitTimeoutError struct {
+	// Path is the polled path.
+	Path string
+	// Attempts is the number of requests made.
+	Attempts int
+	// Last is the last polled result, empty if no request succeeded.
+	Last Res
+	// Err is the context error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("waiting for %s failed after %d attempts: %v", e.Path, e.Attempts, e.Err)
+}
+
+// Unwrap returns the context error, i.e. context.DeadlineExceeded or context.Canceled.
+func (e *WaitTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WaitFailedError is returned by WaitFor if the failure condition is met.
+type WaitFailedError struct {
+	// Path is the polled path.
+	Path string
+	// Res is the result which met the failure condition.
+	Res Res
+}
+
+// Error implements the error interface.
+func (e *WaitFailedError) Error() string {
+	return fmt.Sprintf("waiting for %s failed: %s", e.Path, e.Res.Raw)
+}
+
+// WaitFor polls a path with GET requests until the success or failure condition is met,
+// e.g. to wait for an asynchronous job to complete:
+//
+//	res, err := client.WaitFor(ctx, "/api/v1/jobs/"+id, nd.WaitCondition{
+//		Success: nd.Match("status", "COMPLETED"),
+//		Failure: nd.Match("status", "FAILED"),
+//	}, nd.WaitInterval(2*time.Second), nd.WaitBackoff(2, 30*time.Second), nd.WaitTimeout(10*time.Minute))
+//
+// It returns the result meeting the success condition, a *WaitFailedError if the failure condition is met
+// or a *WaitTimeoutError if the timeout elapsed or ctx is done before. Request errors are returned as is.
+func (client *Client) WaitFor(ctx context.Context, path string, cond WaitCondition, opts ...func(*WaitOptions)) (Res, error) {
+	if cond.Success == nil {
+		return Res{}, fmt.Errorf("waiting for %s failed: no success condition", path)
+	}
+	options := WaitOptions{Interval: DefaultWaitInterval}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultWaitInterval
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	interval := options.Interval
+	var last Res
+	for attempt := 1; ; attempt++ {
+		res, err := client.Get(path, append(options.Mods, Context(ctx))...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Res{}, &WaitTimeoutError{Path: path, Attempts: attempt, Last: last, Err: ctx.Err()}
+			}
+			return Res{}, err
+		}
+		last = res
+		if options.Progress != nil {
+			options.Progress(attempt, res)
+		}
+		if cond.Failure != nil && cond.Failure(res) {
+			return res, &WaitFailedError{Path: path, Res: res}
+		}
+		if cond.Success(res) {
+			return res, nil
+		}
+		if !client.sleep(ctx, interval) {
+			return Res{}, &WaitTimeoutError{Path: path, Attempts: attempt, Last: last, Err: ctx.Err()}
+		}
+		if options.Factor > 1 {
+			interval = time.Duration(float64(interval) * options.Factor)
+			if options.MaxInterval > 0 && interval > options.MaxInterval {
+				interval = options.MaxInterval
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package nd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientWaitFor tests the Client::WaitFor method.
+func TestClientWaitFor(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	cond := WaitCondition{Success: Match("status", "COMPLETED"), Failure: Match("status", "FAILED", "ABORTED")}
+
+	// Success
+	gock.New(testURL).Get("/job").MatchParam("detail", "true").Reply(200).BodyString(`{"status": "RUNNING", "progress": 50}`)
+	gock.New(testURL).Get("/job").MatchParam("detail", "true").Reply(200).BodyString(`{"status": "COMPLETED", "progress": 100}`)
+	var progress []int64
+	res, err := client.WaitFor(context.Background(), "/job", cond,
+		WaitInterval(time.Millisecond),
+		WaitBackoff(2, 5*time.Millisecond),
+		WaitRequest(Query("detail", "true")),
+		WaitProgress(func(attempt int, res Res) {
+			progress = append(progress, res.Get("progress").Int())
+		}))
+	assert.NoError(t, err)
+	assert.Equal(t, "COMPLETED", res.Get("status").String())
+	assert.Equal(t, []int64{50, 100}, progress)
+
+	// Failure
+	gock.New(testURL).Get("/job").Reply(200).BodyString(`{"status": "FAILED"}`)
+	_, err = client.WaitFor(context.Background(), "/job", cond)
+	failedErr := &WaitFailedError{}
+	assert.True(t, errors.As(err, &failedErr))
+	assert.Equal(t, "FAILED", failedErr.Res.Get("status").String())
+
+	// Timeout
+	gock.New(testURL).Get("/job").Persist().Reply(200).BodyString(`{"status": "RUNNING"}`)
+	_, err = client.WaitFor(context.Background(), "/job", cond, WaitInterval(time.Millisecond), WaitTimeout(20*time.Millisecond))
+	timeoutErr := &WaitTimeoutError{}
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "RUNNING", timeoutErr.Last.Get("status").String())
+	assert.Greater(t, timeoutErr.Attempts, 1)
+
+	// Zero interval uses the default interval
+	_, err = client.WaitFor(context.Background(), "/job", cond, WaitInterval(0), WaitTimeout(20*time.Millisecond))
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, 1, timeoutErr.Attempts)
+	gock.Off()
+
+	// Missing success condition
+	gock.New(testURL).Get("/job").Reply(200).BodyString(`{"status": "RUNNING"}`)
+	_, err = client.WaitFor(context.Background(), "/job", WaitCondition{Failure: cond.Failure})
+	assert.ErrorContains(t, err, "no success condition")
+	assert.False(t, gock.IsDone())
+	gock.Off()
+
+	// Request error
+	gock.New(testURL).Get("/job").Reply(404)
+	_, err = client.WaitFor(context.Background(), "/job", cond)
+	assert.True(t, IsNotFound(err))
+}